@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// TestTimestampAccuracy 生成 samples 个 id，测量 id 中的时间和系统时间的最大偏差
+// 每次生成前后各取一次系统时间，id 的时间落在这个区间之外的部分就是偏差
+// 在一些虚拟化或者容器环境中，时钟的精度远低于纳秒，偏差超过 10ms 通常说明虚拟化延迟或者 NTP 配置有问题
+// 注意这会消耗 samples 个 id
+func (s *Snowflake) TestTimestampAccuracy(samples int) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var worst time.Duration
+	for i := 0; i < samples; i++ {
+		before := time.Now().UnixMilli()
+		if _, err := s.nextID(); err != nil {
+			continue
+		}
+		after := time.Now().UnixMilli()
+
+		var d time.Duration
+		if ms := s.time + s.epoch; ms < before {
+			d = time.Duration(before-ms) * time.Millisecond
+		} else if ms > after {
+			d = time.Duration(ms-after) * time.Millisecond
+		}
+		if d > worst {
+			worst = d
+		}
+	}
+
+	return worst
+}
@@ -0,0 +1,27 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTestTimestampAccuracy(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+	if d := s.TestTimestampAccuracy(1000); d != 0 {
+		t.Errorf("deviation %s with the system clock", d)
+	}
+
+	// 一个慢 20ms 的时钟
+	s, err = NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() - 20
+	}))
+	if err != nil {
+		panic(err)
+	}
+	if d := s.TestTimestampAccuracy(10); d < 20*time.Millisecond {
+		t.Errorf("deviation %s, want at least 20ms", d)
+	}
+}
@@ -0,0 +1,78 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// GoroutineAffinityPool 按 goroutine 分配实例的池，每个 goroutine 固定使用同一个实例
+// 适用于长期运行的 goroutine（比如 HTTP 处理或者工作池），goroutine 数量不超过实例数量时，
+// 各个 goroutine 之间不会竞争同一把锁
+type GoroutineAffinityPool struct {
+	// 实例
+	shards []*Snowflake
+	// goroutine id 到实例的映射
+	assigned sync.Map
+	// 下一个分配的实例
+	next uint64
+}
+
+// NewGoroutineAffinityPool 新建一个有 numWorkers 个实例的池
+// 第一个实例的 workerID 由 opts 决定，之后的实例依次加 1，因此池会占用 [workerID, workerID+numWorkers) 的 workerID
+func NewGoroutineAffinityPool(numWorkers int, opts ...Option) (*GoroutineAffinityPool, error) {
+	if numWorkers <= 0 {
+		return nil, errors.New("snowflake: pool needs at least one worker")
+	}
+
+	first, err := NewSnowflake(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &GoroutineAffinityPool{shards: []*Snowflake{first}}
+	for i := 1; i < numWorkers; i++ {
+		w := first.workerID + int64(i)
+		s, err := NewSnowflake(append(opts[:len(opts):len(opts)], WithWorkID(func() (int64, error) {
+			return w, nil
+		}))...)
+		if err != nil {
+			return nil, err
+		}
+		p.shards = append(p.shards, s)
+	}
+
+	return p, nil
+}
+
+// NextID 使用当前 goroutine 对应的实例生成 id，第一次调用时按轮流的方式给 goroutine 分配实例
+// 注意分配关系不会释放，不适合大量短期 goroutine 的场景
+func (p *GoroutineAffinityPool) NextID() (int64, error) {
+	gid := goroutineID()
+
+	s, ok := p.assigned.Load(gid)
+	if !ok {
+		i := atomic.AddUint64(&p.next, 1) - 1
+		s, _ = p.assigned.LoadOrStore(gid, p.shards[i%uint64(len(p.shards))])
+	}
+
+	return s.(*Snowflake).NextID()
+}
+
+// goroutineID 从 runtime.Stack 中解析当前 goroutine 的 id
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+
+	// 格式为 "goroutine 123 [running]:..."
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i >= 0 {
+		b = b[:i]
+	}
+
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGoroutineAffinityPool(t *testing.T) {
+	p, err := NewGoroutineAffinityPool(4, WithWorkID(func() (int64, error) { return 8, nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mutex sync.Mutex
+		seen  = make(map[int64]bool)
+		wg    sync.WaitGroup
+	)
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				id, err := p.NextID()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				mutex.Lock()
+				if seen[id] {
+					t.Errorf("duplicate id %d", id)
+				}
+				seen[id] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, s := range p.shards {
+		if s.WorkerID() != 8+int64(i) {
+			t.Errorf("shard %d worker = %d", i, s.WorkerID())
+		}
+	}
+	if goroutineID() == 0 {
+		t.Error("failed to parse goroutine id")
+	}
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// BackfillID 使用本实例的结构为历史记录构造一个 id，时间为 t，workerID 为本实例的 workerID，序列号为 seq
+// 用于数据迁移时补录带历史时间的记录，不会推进序列号，也不会更新 lastTime
+// t 早于 epoch、时间部分溢出、seq 超出序列号部分的范围时返回错误
+// 注意同一个时间、同一个 seq 得到的 id 相同，调用方需要自己保证 seq 不重复
+func (s *Snowflake) BackfillID(t time.Time, seq int64) (int64, error) {
+	return s.IDAt(t, s.workerID, seq)
+}
+
+// IDAt 使用本实例的 epoch 和结构构造时间为 t 的 id，是 TimeFromID 的逆运算
+// 不会推进序列号，也不会更新 lastTime；t 早于 epoch、时间部分溢出、workerID 或 seq 超出范围时返回错误
+func (s *Snowflake) IDAt(t time.Time, workerID, seq int64) (int64, error) {
+	if workerID < 0 || workerID >= 1<<s.bitLenWorkerID {
+		return 0, fmt.Errorf("snowflake: worker id %d out of range [0, %d)", workerID, int64(1)<<s.bitLenWorkerID)
+	}
+	if seq < 0 || seq > s.sequenceMask {
+		return 0, fmt.Errorf("snowflake: sequence %d out of range [0, %d]", seq, s.sequenceMask)
+	}
+
+	field, err := s.timeField(t)
+	if err != nil {
+		return 0, err
+	}
+	if s.descending {
+		field = 1<<s.bitLenTime - 1 - field
+	}
+
+	return s.compose(field, workerID, seq), nil
+}
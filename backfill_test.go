@@ -0,0 +1,65 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackfillID(t *testing.T) {
+	s, err := NewSnowflake(WithEpoch(1288834974657))
+	if err != nil {
+		panic(err)
+	}
+
+	last := next(t, s)
+	at := time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	id, err := s.BackfillID(at, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.TimeFromID(id).Equal(at) {
+		t.Errorf("time = %s, want %s", s.TimeFromID(id), at)
+	}
+	if _, w, seq := s.decompose(id); w != s.WorkerID() || seq != 5 {
+		t.Errorf("worker, sequence = %d, %d, want %d, 5", w, seq, s.WorkerID())
+	}
+	if s.LastID() != last {
+		t.Error("backfill should not change the generator state")
+	}
+
+	if _, err = s.BackfillID(time.UnixMilli(s.Epoch()-1), 0); err == nil {
+		t.Error("expected error for time before epoch")
+	}
+	if _, err = s.BackfillID(at, s.SequenceMask()+1); err == nil {
+		t.Error("expected error for sequence out of range")
+	}
+	if _, err = s.BackfillID(time.UnixMilli(s.Epoch()+1<<41), 0); err == nil {
+		t.Error("expected error for overflowing time")
+	}
+}
+
+func TestIDAt(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithNonIncrement()}, {WithDescendingSequence()}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		at := time.Now().Truncate(time.Millisecond)
+		id, err := s.IDAt(at, 3, 4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.TimeFromID(id).Equal(at) {
+			t.Errorf("time = %s, want %s", s.TimeFromID(id), at)
+		}
+		if _, w, seq := s.decompose(id); w != 3 || seq != 4 {
+			t.Errorf("worker, sequence = %d, %d, want 3, 4", w, seq)
+		}
+
+		if _, err = s.IDAt(at, 1<<s.bitLenWorkerID, 0); err == nil {
+			t.Error("expected error for worker id out of range")
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+// NextMillisecondBatch 一次性生成当前这一毫秒剩下的所有 id，用于吞吐量要求很高的场景
+// 从当前的序列号开始，直到这一毫秒的序列号用完，expiresAt 为下一毫秒的开始时间
+// 之后再生成 id 时会等待下一毫秒，和序列号用完时的行为一致
+// 设置了 WithRandomSequence 时没有自增的计数，返回错误
+func (s *Snowflake) NextMillisecondBatch() (ids []int64, expiresAt time.Time, err error) {
+	if s.randomSequence {
+		return nil, time.Time{}, errors.New("snowflake: cannot batch with random sequence")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	ids = append(ids, id)
+
+	t, _, _ := s.decompose(id)
+	for {
+		seq := (s.sequenceID + 1) & s.counterMask()
+		if s.descending {
+			seq = (s.sequenceID - 1) & s.counterMask()
+		}
+		if seq == s.sequenceStart {
+			break
+		}
+
+		s.sequenceID = seq
+		s.lastID = s.withChecksum(s.compose(t, s.workerID, s.sequenceField()))
+		ids = append(ids, s.lastID)
+	}
+
+	return ids, time.UnixMilli(s.lastTime + 1), nil
+}
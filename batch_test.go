@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextMillisecondBatch(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		ids, expiresAt, err := s.NextMillisecondBatch()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) == 0 || int64(len(ids)) > s.sequenceMask+1 {
+			t.Fatalf("got %d ids", len(ids))
+		}
+
+		seen := make(map[int64]bool)
+		for _, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate id %d", id)
+			}
+			seen[id] = true
+		}
+
+		// 序列号已经用完，下一个 id 在 expiresAt 之后
+		id := next(t, s)
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		if s.lastTime < expiresAt.UnixMilli() {
+			t.Errorf("next id time %d before %s", s.lastTime, expiresAt.Format(time.RFC3339Nano))
+		}
+	}
+
+	s, _ := NewSnowflake(WithRandomSequence())
+	if _, _, err := s.NextMillisecondBatch(); err == nil {
+		t.Error("expected error for random sequence")
+	}
+}
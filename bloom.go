@@ -0,0 +1,108 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+// IDBloomFilter 用于检测重复 id 的布隆过滤器，不需要保存所有的 id
+// 位数组大小 m = -n*ln(p)/(ln2)^2，哈希函数个数 k = m/n*ln2，其中 n 为容量，p 为误判率，
+// 加入 n 个 id 后，误判率约为 (1-e^(-kn/m))^k ≈ p，超过容量后误判率会迅速上升
+// 不会漏判：加入过的 id，MightHaveSeen 一定返回 true
+type IDBloomFilter struct {
+	// 锁
+	mutex sync.RWMutex
+
+	// 位数组
+	bits []uint64
+	// 位数组大小
+	m uint64
+	// 哈希函数个数
+	k uint64
+}
+
+// NewIDBloomFilter 新建一个容量为 capacity，误判率为 falsePositiveRate 的布隆过滤器
+func NewIDBloomFilter(capacity int, falsePositiveRate float64) *IDBloomFilter {
+	if capacity < 1 {
+		capacity = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.01
+	}
+
+	n := float64(capacity)
+	m := uint64(math.Ceil(-n * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / n * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &IDBloomFilter{
+		bits: make([]uint64, (m+63)/64),
+		m:    m,
+		k:    k,
+	}
+}
+
+// Add 加入一个 id
+func (f *IDBloomFilter) Add(id int64) {
+	h1, h2 := bloomHash(id)
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	for i := uint64(0); i < f.k; i++ {
+		j := (h1 + i*h2) % f.m
+		f.bits[j/64] |= 1 << (j % 64)
+	}
+}
+
+// MightHaveSeen 判断 id 是否可能加入过，返回 false 时一定没有加入过
+func (f *IDBloomFilter) MightHaveSeen(id int64) bool {
+	h1, h2 := bloomHash(id)
+
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	for i := uint64(0); i < f.k; i++ {
+		j := (h1 + i*h2) % f.m
+		if f.bits[j/64]&(1<<(j%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// EstimatedCount 根据被置位的位数估算加入过的不同 id 的个数，即 -m/k*ln(1-X/m)，X 为被置位的位数
+func (f *IDBloomFilter) EstimatedCount() int {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+
+	var x int
+	for _, w := range f.bits {
+		x += bits.OnesCount64(w)
+	}
+	if uint64(x) >= f.m {
+		return math.MaxInt32
+	}
+
+	m, k := float64(f.m), float64(f.k)
+	return int(math.Round(-m / k * math.Log(1-float64(x)/m)))
+}
+
+// bloomHash 使用 FNV-1a 和 FNV-1 两个哈希，通过双重哈希得到 k 个哈希函数
+func bloomHash(id int64) (h1, h2 uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+
+	a := fnv.New64a()
+	_, _ = a.Write(b[:])
+	c := fnv.New64()
+	_, _ = c.Write(b[:])
+
+	// h2 为奇数，保证各个哈希函数不同
+	return a.Sum64(), c.Sum64() | 1
+}
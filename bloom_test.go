@@ -0,0 +1,40 @@
+package snowflake
+
+import "testing"
+
+func TestIDBloomFilter(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	const (
+		n = 10000
+		p = 0.01
+	)
+
+	f := NewIDBloomFilter(n, p)
+	for i := 0; i < n; i++ {
+		id := next(t, s)
+		f.Add(id)
+		if !f.MightHaveSeen(id) {
+			t.Fatalf("id %d added but not seen", id)
+		}
+	}
+
+	if c := f.EstimatedCount(); c < n*9/10 || c > n*11/10 {
+		t.Errorf("estimated count %d, want about %d", c, n)
+	}
+
+	// 之后生成的 id 一定没有加入过，统计误判率
+	fp := 0
+	const trials = 100000
+	for i := 0; i < trials; i++ {
+		if f.MightHaveSeen(next(t, s)) {
+			fp++
+		}
+	}
+	if rate := float64(fp) / trials; rate > 2*p {
+		t.Errorf("false positive rate %.4f exceeds %.4f", rate, 2*p)
+	}
+}
@@ -0,0 +1,124 @@
+package snowflake
+
+import (
+	"errors"
+	"expvar"
+	"math"
+	"time"
+)
+
+// CurrentCapacity 返回当前这一毫秒剩余的序列号数量，以及每毫秒的序列号总数，用于在出现延迟之前发现容量不足
+// 剩余的数量和 MaxSequencesRemaining 相同，已经进入新的一毫秒时为总数
+// 设置了类型标签、优先级、校验和时，总数为计数部分能表示的数量
+func (s *Snowflake) CurrentCapacity() (remainingInMs, totalPerMs int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.capacity()
+}
+
+// UtilizationPercent 返回当前这一毫秒序列号的使用率，为已经使用的序列号数量 / 总数 * 100
+func (s *Snowflake) UtilizationPercent() float64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return utilization(s.capacity())
+}
+
+// utilization 根据剩余数量和总数计算使用率
+func utilization(remaining, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(total-remaining) / float64(total) * 100
+}
+
+// EstimateRemainingLifetime 估计时间部分还有多久溢出，用于容量规划和告警
+// 溢出时间为 epoch + (1<<bitLenTime - 1) 毫秒，已经溢出时返回错误，超出 time.Duration 的范围时返回最大值
+func (s *Snowflake) EstimateRemainingLifetime() (time.Duration, error) {
+	maxTimeMs := int64(1)<<s.bitLenTime - 1
+	left := maxTimeMs - (s.clock() - s.epoch)
+	if left <= 0 {
+		return 0, errors.New("snowflake: time bits have overflowed")
+	}
+	if left > math.MaxInt64/int64(time.Millisecond) {
+		return math.MaxInt64, nil
+	}
+	return time.Duration(left) * time.Millisecond, nil
+}
+
+// MaxSequencesRemaining 返回不需要等待下一毫秒还能生成的 id 数量，用于突发生成大量 id 之前判断是否会中途停顿
+// 这一毫秒的序列号用完时为 0，进入新的一毫秒时为每毫秒的序列号总数
+func (s *Snowflake) MaxSequencesRemaining() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.sequencesRemaining()
+}
+
+// Stats 实例的运行指标
+type Stats struct {
+	// 当前这一毫秒剩余的序列号数量，见 CurrentCapacity，和 MaxSequencesRemaining 相同
+	RemainingInMs int64
+	// 每毫秒的序列号总数
+	TotalPerMs int64
+	// 当前这一毫秒序列号的使用率，见 UtilizationPercent
+	UtilizationPercent float64
+	// 不需要等待下一毫秒还能生成的 id 数量，见 MaxSequencesRemaining
+	MaxSequencesRemaining int64
+	// 时间部分还有多久溢出，已经溢出时为 0，见 EstimateRemainingLifetime
+	RemainingLifetime time.Duration
+}
+
+// Stats 返回实例当前的运行指标，序列号相关的指标在同一次加锁中得到
+func (s *Snowflake) Stats() Stats {
+	var st Stats
+
+	s.mutex.Lock()
+	st.RemainingInMs, st.TotalPerMs = s.capacity()
+	st.UtilizationPercent = utilization(st.RemainingInMs, st.TotalPerMs)
+	st.MaxSequencesRemaining = s.sequencesRemaining()
+	s.mutex.Unlock()
+
+	st.RemainingLifetime, _ = s.EstimateRemainingLifetime()
+
+	return st
+}
+
+// RegisterExpvars 把实例的运行指标注册到 expvar 的 name 下，通过 /debug/vars 查看
+// 注意 expvar 不允许重复注册，同一个 name 只能调用一次，否则会 panic
+func (s *Snowflake) RegisterExpvars(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return s.expvars()
+	}))
+}
+
+// expvars 返回 RegisterExpvars 导出的指标
+func (s *Snowflake) expvars() map[string]interface{} {
+	st := s.Stats()
+
+	return map[string]interface{}{
+		"remaining_in_ms":          st.RemainingInMs,
+		"total_per_ms":             st.TotalPerMs,
+		"utilization_percent":      st.UtilizationPercent,
+		"max_sequences_remaining":  st.MaxSequencesRemaining,
+		"remaining_lifetime_hours": st.RemainingLifetime.Hours(),
+	}
+}
+
+// capacity 计算剩余的序列号数量和总数，调用方需要持有锁
+func (s *Snowflake) capacity() (remaining, total int64) {
+	return s.sequencesRemaining(), s.counterMask() + 1
+}
+
+// sequencesRemaining 计算这一毫秒序列号回到起始值之前还能生成的 id 数量，调用方需要持有锁
+func (s *Snowflake) sequencesRemaining() int64 {
+	if s.randomSequence || s.clock() > s.lastTime {
+		return s.counterMask() + 1
+	}
+
+	if s.descending {
+		return (s.sequenceID - s.sequenceStart - 1) & s.counterMask()
+	}
+	return (s.sequenceStart - s.sequenceID - 1) & s.counterMask()
+}
@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"expvar"
+	"testing"
+	"time"
+)
+
+func TestCurrentCapacity(t *testing.T) {
+	opts := [][]Option{nil, {WithDescendingSequence()}, {WithReplicaSequenceOffset(100)}, {WithEntropySource(rand.Reader)}}
+	for _, o := range opts {
+		now := time.Now().UnixMilli()
+		s, err := NewSnowflake(append(o, WithClock(func() int64 { return now }))...)
+		if err != nil {
+			panic(err)
+		}
+
+		for i := 0; i < 100; i++ {
+			next(t, s)
+		}
+
+		remaining, total := s.CurrentCapacity()
+		if total != s.SequenceMask()+1 {
+			t.Errorf("total = %d, want %d", total, s.SequenceMask()+1)
+		}
+		if remaining != total-100 {
+			t.Errorf("remaining = %d, want %d", remaining, total-100)
+		}
+		if got := s.MaxSequencesRemaining(); got != remaining {
+			t.Errorf("MaxSequencesRemaining = %d, want %d", got, remaining)
+		}
+		if st := s.Stats(); st.RemainingInMs != remaining || st.MaxSequencesRemaining != remaining {
+			t.Errorf("stats = %+v, want %d remaining", st, remaining)
+		}
+		if p, want := s.UtilizationPercent(), float64(100)/float64(total)*100; p != want {
+			t.Errorf("utilization = %f, want %f", p, want)
+		}
+
+		// 进入新的一毫秒后容量恢复
+		now++
+		if remaining, total = s.CurrentCapacity(); remaining != total {
+			t.Errorf("remaining = %d, want %d", remaining, total)
+		}
+	}
+}
+
+func TestRegisterExpvars(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	s.RegisterExpvars("snowflake_test")
+
+	var vars map[string]interface{}
+	if err = json.Unmarshal([]byte(expvar.Get("snowflake_test").String()), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if vars["total_per_ms"] != float64(s.SequenceMask()+1) {
+		t.Errorf("total_per_ms = %v", vars["total_per_ms"])
+	}
+}
+
+func TestEstimateRemainingLifetime(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	left, err := s.EstimateRemainingLifetime()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.UnixMilli(1<<41 - 1).Sub(time.Now())
+	if d := left - want; d < -time.Second || d > time.Second {
+		t.Errorf("lifetime = %s, want about %s", left, want)
+	}
+
+	// 时间部分很长时不会溢出 time.Duration
+	if s, err = NewSnowflake(WithLen(53, 0, 10), WithWorkID(func() (int64, error) { return 0, nil })); err != nil {
+		t.Fatal(err)
+	}
+	if left, err = s.EstimateRemainingLifetime(); err != nil || left <= 0 {
+		t.Errorf("lifetime = %s, %v", left, err)
+	}
+
+	s.SetBitLenTime(30)
+	if _, err = s.EstimateRemainingLifetime(); err == nil {
+		t.Error("expected error for overflowed time bits")
+	}
+}
+
+func TestMaxSequencesRemaining(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}, {WithReplicaSequenceOffset(100)}} {
+		now := time.Now().UnixMilli()
+		s, err := NewSnowflake(append(opts, WithClock(func() int64 { return now }))...)
+		if err != nil {
+			panic(err)
+		}
+
+		total := s.SequenceMask() + 1
+		if got := s.MaxSequencesRemaining(); got != total {
+			t.Errorf("remaining before the first id = %d, want %d", got, total)
+		}
+
+		next(t, s)
+		if got := s.MaxSequencesRemaining(); got != total-1 {
+			t.Errorf("remaining after one id = %d, want %d", got, total-1)
+		}
+
+		for s.MaxSequencesRemaining() > 0 {
+			next(t, s)
+		}
+		if st := s.Stats(); st.MaxSequencesRemaining != 0 || st.TotalPerMs != total {
+			t.Errorf("stats = %+v", st)
+		}
+
+		now++
+		if got := s.MaxSequencesRemaining(); got != total {
+			t.Errorf("remaining in a new millisecond = %d, want %d", got, total)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package snowflake
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrMaxWaitExceeded 等待时间超过了 WithMaxWait 设置的最长时间
+	ErrMaxWaitExceeded = errors.New("snowflake: max wait exceeded")
+	// ErrMaxRetriesExceeded 重试次数超过了 WithMaxRetries 设置的最大次数
+	ErrMaxRetriesExceeded = errors.New("snowflake: max retries exceeded")
+	// ErrDescendingSequence 设置了 WithDescendingSequence 时新的 id 越来越小，无法生成严格大于某个 id 的 id
+	ErrDescendingSequence = errors.New("snowflake: ids are not increasing with WithDescendingSequence")
+)
+
+const (
+	// defaultMaxWait 默认最长等待时间，和时间回拨时的等待时间一致
+	defaultMaxWait = time.Second
+	// defaultMaxRetries 默认最大重试次数
+	defaultMaxRetries = 10000
+)
+
+// WithMaxWait 自定义 NextCausalID、NextEventID 等需要等待的方法的最长等待时间，默认 1s
+func WithMaxWait(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.maxWait = d
+	}
+}
+
+// WithMaxRetries 自定义 NextVersionID 等需要重试的方法的最大重试次数，默认 10000
+func WithMaxRetries(n int) Option {
+	return func(s *Snowflake) {
+		s.maxRetries = n
+	}
+}
+
+// NextCausalID 生成一个严格大于 dependsOnID 的 id，用于事件溯源中保证结果事件排在命令之后
+// 如果 dependsOnID 的时间比当前时间晚（比如来自时钟稍快的机器），会自旋等待时钟追上，而不是睡眠
+// 超过最长等待时间则返回 ErrMaxWaitExceeded，避免无限等待；设置了倒序时返回 ErrDescendingSequence
+func (s *Snowflake) NextCausalID(dependsOnID int64) (int64, error) {
+	if s.descending {
+		return 0, ErrDescendingSequence
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.nextIDGreaterThan(dependsOnID)
+}
+
+// NextEventID 生成一个严格大于 prevEventID 的 id，用于只追加的事件日志
+// 如果生成的 id 不大于 prevEventID（时间回拨或者序列号重置导致），会继续推进序列号，
+// 序列号用完后等待下一毫秒，直到结果大于 prevEventID
+// 超过最长等待时间仍然没有推进则返回 ErrMaxWaitExceeded；设置了倒序时返回 ErrDescendingSequence
+func (s *Snowflake) NextEventID(prevEventID int64) (int64, error) {
+	if s.descending {
+		return 0, ErrDescendingSequence
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.nextIDGreaterThan(prevEventID)
+}
+
+// NextIDAfter 生成一个时间不早于 minTime 的 id，用于要求相邻 id 至少间隔一段时间的协议（比如日志文件轮转）
+// 当前时间早于 minTime 时会自旋等待时钟追上，minTime 比当前时间晚超过最长等待时间则直接返回 ErrMaxWaitExceeded
+func (s *Snowflake) NextIDAfter(minTime time.Time) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	minMs := minTime.UnixMilli()
+	if minMs > s.clock()+s.maxWait.Milliseconds() {
+		return 0, ErrMaxWaitExceeded
+	}
+	for s.clock() < minMs {
+	}
+
+	return s.nextID()
+}
+
+// nextIDGreaterThan 生成一个严格大于 prev 的 id，调用方需要持有锁
+func (s *Snowflake) nextIDGreaterThan(prev int64) (int64, error) {
+	deadline := s.clock() + s.maxWait.Milliseconds()
+
+	// prev 的时间比当前时间晚，先自旋等待时钟追上
+	t, _, _ := s.decompose(prev)
+	if t+s.epoch > deadline {
+		return 0, ErrMaxWaitExceeded
+	}
+	for s.clock()-s.epoch < t {
+	}
+
+	for {
+		id, err := s.nextID()
+		if err != nil {
+			return 0, err
+		}
+		if id > prev {
+			return id, nil
+		}
+		if s.clock() > deadline {
+			return 0, ErrMaxWaitExceeded
+		}
+	}
+}
+
+// NextVersionID 生成一个严格大于 previousVersion 的 id，用于乐观锁的版本号
+// 正常情况下一次就能生成，只有时间回拨等情况下才需要重试，每次重试都会推进序列号，
+// 超过最大重试次数则返回 ErrMaxRetriesExceeded，设置了倒序时返回 ErrDescendingSequence
+// 这样就不需要单独维护一个版本号的计数器
+func (s *Snowflake) NextVersionID(previousVersion int64) (int64, error) {
+	if s.descending {
+		return 0, ErrDescendingSequence
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := 0; i <= s.maxRetries; i++ {
+		id, err := s.nextID()
+		if err != nil {
+			return 0, err
+		}
+		if id > previousVersion {
+			return id, nil
+		}
+	}
+
+	return 0, ErrMaxRetriesExceeded
+}
@@ -0,0 +1,136 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextCausalID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	// 依赖的 id 来自一台快 50ms 的机器
+	fast, err := NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() + 50
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	dep := next(t, fast)
+	id, err := s.NextCausalID(dep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= dep {
+		t.Errorf("id %d not greater than %d", id, dep)
+	}
+
+	future, _ := NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() + time.Hour.Milliseconds()
+	}))
+	if _, err = s.NextCausalID(next(t, future)); err == nil {
+		t.Error("expected error for id far in the future")
+	}
+}
+
+func TestNextEventID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	prev := next(t, s)
+	for i := 0; i < 100; i++ {
+		id, err := s.NextEventID(prev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev {
+			t.Fatalf("id %d not greater than %d", id, prev)
+		}
+		prev = id
+	}
+
+	// 最长等待 10ms，依赖的 id 快 50ms
+	s, _ = NewSnowflake(WithMaxWait(10 * time.Millisecond))
+	fast, _ := NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() + 50
+	}))
+	if _, err = s.NextEventID(next(t, fast)); err != ErrMaxWaitExceeded {
+		t.Errorf("err = %v, want ErrMaxWaitExceeded", err)
+	}
+}
+
+func TestNextIDAfter(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	minTime := time.Now().Add(20 * time.Millisecond)
+	id, err := s.NextIDAfter(minTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tm, _, _ := s.decompose(id); tm+s.epoch < minTime.UnixMilli() {
+		t.Errorf("id time %d before %d", tm+s.epoch, minTime.UnixMilli())
+	}
+
+	if _, err = s.NextIDAfter(time.Now().Add(time.Hour)); err != ErrMaxWaitExceeded {
+		t.Errorf("err = %v, want %v", err, ErrMaxWaitExceeded)
+	}
+}
+
+func TestNextVersionID(t *testing.T) {
+	s, err := NewSnowflake(WithMaxRetries(3))
+	if err != nil {
+		panic(err)
+	}
+
+	prev := next(t, s)
+	id, err := s.NextVersionID(prev)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id <= prev {
+		t.Errorf("id %d not greater than %d", id, prev)
+	}
+
+	future, _ := NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() + time.Hour.Milliseconds()
+	}))
+	if _, err = s.NextVersionID(next(t, future)); err != ErrMaxRetriesExceeded {
+		t.Errorf("err = %v, want ErrMaxRetriesExceeded", err)
+	}
+}
+
+func TestCausalDescending(t *testing.T) {
+	s, err := NewSnowflake(WithDescendingSequence())
+	if err != nil {
+		panic(err)
+	}
+
+	prev := next(t, s)
+	start := time.Now()
+	if _, err = s.NextCausalID(prev); err != ErrDescendingSequence {
+		t.Errorf("NextCausalID: err = %v, want %v", err, ErrDescendingSequence)
+	}
+	if _, err = s.NextEventID(prev); err != ErrDescendingSequence {
+		t.Errorf("NextEventID: err = %v, want %v", err, ErrDescendingSequence)
+	}
+	if _, err = s.NextVersionID(prev); err != ErrDescendingSequence {
+		t.Errorf("NextVersionID: err = %v, want %v", err, ErrDescendingSequence)
+	}
+	// 直接返回错误，不会等待
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("took %s", d)
+	}
+
+	// NextIDAfter 只要求时间，倒序时仍然可用
+	if _, err = s.NextIDAfter(time.Now()); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,55 @@
+package snowflake
+
+// maxChecksumBits 校验和的最大 bit 长度
+const maxChecksumBits = 8
+
+// WithChecksumBits 在序列号的最低 n 位放一个校验和，用于检测保存在不可靠介质中的 id 是否损坏
+// 校验和为 id 其它所有位按 n 位一组的异或，只能发现简单的损坏，不能防止伪造
+// 每毫秒可用的序列号减少为 1/2^n，要求 0 <= n <= 8，否则 NewSnowflake 返回错误
+func WithChecksumBits(n int) Option {
+	return func(s *Snowflake) {
+		s.checksumBits = int64(n)
+	}
+}
+
+// NextIDWithChecksum 生成一个 id，并单独返回其中的校验和
+func (s *Snowflake) NextIDWithChecksum() (id int64, checksum uint8, err error) {
+	if id, err = s.NextID(); err != nil {
+		return 0, 0, err
+	}
+
+	_, _, seq := s.decompose(id)
+	return id, uint8(seq & (1<<s.checksumBits - 1)), nil
+}
+
+// ValidateChecksum 校验 WithChecksumBits 生成的 id 的校验和是否正确
+func ValidateChecksum(id int64, layout BitLayout, checksumBits int) bool {
+	n := int64(checksumBits)
+	mask := int64(1)<<n - 1
+
+	t, w, seq := layout.Decompose(id)
+	return checksum(layout.Compose(t, w, seq&^mask), n) == seq&mask
+}
+
+// withChecksum 把校验和填到 id 的序列号最低位，id 中校验和的位置需要为 0
+func (s *Snowflake) withChecksum(id int64) int64 {
+	if s.checksumBits == 0 {
+		return id
+	}
+
+	t, w, seq := s.decompose(id)
+	return s.compose(t, w, seq|checksum(id, s.checksumBits))
+}
+
+// checksum 把 id 按 n 位一组异或，得到 n 位的校验和
+func checksum(id int64, n int64) int64 {
+	if n <= 0 {
+		return 0
+	}
+
+	var c int64
+	for u := uint64(id); u != 0; u >>= uint64(n) {
+		c ^= int64(u & (1<<n - 1))
+	}
+	return c
+}
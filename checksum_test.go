@@ -0,0 +1,42 @@
+package snowflake
+
+import "testing"
+
+func TestWithChecksumBits(t *testing.T) {
+	for _, opts := range [][]Option{{WithChecksumBits(4)}, {WithChecksumBits(4), WithNonIncrement()}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		seen := make(map[int64]bool)
+		for i := 0; i < 200; i++ {
+			id, sum, err := s.NextIDWithChecksum()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[id] {
+				t.Fatalf("duplicate id %d", id)
+			}
+			seen[id] = true
+
+			if sum >= 1<<4 {
+				t.Fatalf("checksum %d does not fit in 4 bits", sum)
+			}
+			if !ValidateChecksum(id, s.Layout(), 4) {
+				t.Fatalf("id %d: checksum should be valid", id)
+			}
+
+			// 翻转一位后校验失败
+			if ValidateChecksum(id^1<<20, s.Layout(), 4) {
+				t.Fatalf("id %d: corrupted id should be invalid", id)
+			}
+		}
+	}
+
+	for _, n := range []int{-1, 9} {
+		if _, err := NewSnowflake(WithChecksumBits(n)); err == nil {
+			t.Errorf("%d bits: expected error", n)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	// gcpMetadataEndpoint GCP 元数据服务中实例 id 的地址
+	gcpMetadataEndpoint = "http://metadata.google.internal/computeMetadata/v1/instance/id"
+	// azureIMDSEndpoint Azure 实例元数据服务的地址
+	azureIMDSEndpoint = "http://169.254.169.254/metadata/instance?api-version=2021-02-01"
+)
+
+// CloudOption 云平台 WorkerID 的可选配置
+type CloudOption func(c *cloudConfig)
+
+// cloudConfig 云平台 WorkerID 的配置
+type cloudConfig struct {
+	// 元数据服务的地址
+	endpoint string
+}
+
+// WithGCPMetadataEndpoint 自定义 GCP 元数据服务的地址，一般用于测试
+func WithGCPMetadataEndpoint(url string) CloudOption {
+	return func(c *cloudConfig) {
+		c.endpoint = url
+	}
+}
+
+// WithAzureIMDSEndpoint 自定义 Azure 实例元数据服务的地址，一般用于测试
+func WithAzureIMDSEndpoint(url string) CloudOption {
+	return func(c *cloudConfig) {
+		c.endpoint = url
+	}
+}
+
+// GCPWorkerID 使用 GCP Compute Engine 实例 id 生成 workerID
+// 实例 id 从元数据服务获取，是稳定且唯一的数字，截取低 bitLenWorkerID 位作为 workerID，成功后会缓存结果
+// 元数据服务不可用时（比如不在 GCP 上运行），退回到默认的通过 IP 生成的方式
+// 使用 WithLen 自定义了 workerID 长度时，请使用 WithGCPWorkerID
+func GCPWorkerID(ctx context.Context, opts ...CloudOption) WorkerID {
+	return gcpWorkerID(ctx, func() int64 { return bitLenWorkerID }, opts...)
+}
+
+// WithGCPWorkerID 使用 GCP Compute Engine 实例 id 作为 workerID，截取为实例配置的 workerID 长度，见 GCPWorkerID
+func WithGCPWorkerID(ctx context.Context, opts ...CloudOption) Option {
+	return func(s *Snowflake) {
+		s.w = gcpWorkerID(ctx, func() int64 { return s.bitLenWorkerID }, opts...)
+	}
+}
+
+// gcpWorkerID 获取 GCP 实例 id，截取低 bits() 位作为 workerID
+func gcpWorkerID(ctx context.Context, bits func() int64, opts ...CloudOption) WorkerID {
+	c := &cloudConfig{endpoint: gcpMetadataEndpoint}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return cachedWorkerID(func() (int64, error) {
+		body, err := fetchMetadata(ctx, c.endpoint, "Metadata-Flavor", "Google")
+		if err != nil {
+			return 0, err
+		}
+
+		id, err := strconv.ParseUint(strings.TrimSpace(string(body)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("snowflake: invalid gcp instance id %q: %w", body, err)
+		}
+
+		return int64(id & (1<<bits() - 1)), nil
+	})
+}
+
+// AzureWorkerID 使用 Azure 虚拟机的 vmId 生成 workerID
+// vmId 从实例元数据服务获取，是一个稳定的 UUID，对它的 16 个字节计算 FNV-1a 后截取低 bitLenWorkerID 位作为 workerID，成功后会缓存结果
+// 超时通过 ctx 控制，元数据服务不可用时退回到默认的通过 IP 生成的方式
+// 使用 WithLen 自定义了 workerID 长度时，请使用 WithAzureWorkerID
+func AzureWorkerID(ctx context.Context, opts ...CloudOption) WorkerID {
+	return azureWorkerID(ctx, func() int64 { return bitLenWorkerID }, opts...)
+}
+
+// WithAzureWorkerID 使用 Azure 虚拟机的 vmId 生成 workerID，截取为实例配置的 workerID 长度，见 AzureWorkerID
+func WithAzureWorkerID(ctx context.Context, opts ...CloudOption) Option {
+	return func(s *Snowflake) {
+		s.w = azureWorkerID(ctx, func() int64 { return s.bitLenWorkerID }, opts...)
+	}
+}
+
+// azureWorkerID 获取 Azure 虚拟机的 vmId，对它的 16 个字节计算哈希后截取低 bits() 位作为 workerID
+func azureWorkerID(ctx context.Context, bits func() int64, opts ...CloudOption) WorkerID {
+	c := &cloudConfig{endpoint: azureIMDSEndpoint}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return cachedWorkerID(func() (int64, error) {
+		body, err := fetchMetadata(ctx, c.endpoint, "Metadata", "true")
+		if err != nil {
+			return 0, err
+		}
+
+		var m struct {
+			Compute struct {
+				VMID string `json:"vmId"`
+			} `json:"compute"`
+		}
+		if err = json.Unmarshal(body, &m); err != nil {
+			return 0, fmt.Errorf("snowflake: invalid azure metadata: %w", err)
+		}
+		if m.Compute.VMID == "" {
+			return 0, fmt.Errorf("snowflake: azure metadata has no vmId")
+		}
+
+		// vmId 的格式为 xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+		u, err := hex.DecodeString(strings.ReplaceAll(m.Compute.VMID, "-", ""))
+		if err != nil || len(u) != 16 {
+			return 0, fmt.Errorf("snowflake: invalid azure vmId %q", m.Compute.VMID)
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write(u)
+
+		return int64(h.Sum64() & (1<<bits() - 1)), nil
+	})
+}
+
+// cachedWorkerID 缓存 w 成功返回的 workerID，失败时退回到 defaultWorkerID
+func cachedWorkerID(w WorkerID) WorkerID {
+	var (
+		mutex sync.Mutex
+		id    int64
+		done  bool
+	)
+
+	return func() (int64, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if done {
+			return id, nil
+		}
+
+		v, err := w()
+		if err != nil {
+			return defaultWorkerID()
+		}
+		id, done = v, true
+
+		return id, nil
+	}
+}
+
+// fetchMetadata 请求云平台的元数据服务
+func fetchMetadata(ctx context.Context, url, header, value string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(header, value)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("snowflake: metadata %s returned %s", url, resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"context"
+	"hash/fnv"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGCPWorkerID(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("1234567890123\n"))
+	}))
+	defer srv.Close()
+
+	w := GCPWorkerID(context.Background(), WithGCPMetadataEndpoint(srv.URL))
+	for i := 0; i < 2; i++ {
+		id, err := w()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := int64(1234567890123 & (1<<bitLenWorkerID - 1)); id != want {
+			t.Errorf("id = %d, want %d", id, want)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("metadata fetched %d times, want 1", calls)
+	}
+
+	// 自定义 workerID 长度时截取为实例的长度
+	s, err := NewSnowflake(WithLen(41, 8, 14), WithGCPWorkerID(context.Background(), WithGCPMetadataEndpoint(srv.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(1234567890123 & (1<<8 - 1)); s.WorkerID() != want {
+		t.Errorf("worker id = %d, want %d", s.WorkerID(), want)
+	}
+}
+
+func TestAzureWorkerID(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"compute":{"vmId":"02aab8a4-74ef-476e-8182-f6d2ba4166a6"}}`))
+	}))
+	defer srv.Close()
+
+	w := AzureWorkerID(context.Background(), WithAzureIMDSEndpoint(srv.URL))
+	a, err := w()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := w()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b || a < 0 || a >= 1<<bitLenWorkerID {
+		t.Errorf("got %d and %d", a, b)
+	}
+
+	// 哈希的是 UUID 的 16 个字节，而不是字符串
+	h := fnv.New64a()
+	_, _ = h.Write([]byte{0x02, 0xaa, 0xb8, 0xa4, 0x74, 0xef, 0x47, 0x6e, 0x81, 0x82, 0xf6, 0xd2, 0xba, 0x41, 0x66, 0xa6})
+	if want := int64(h.Sum64() & (1<<bitLenWorkerID - 1)); a != want {
+		t.Errorf("id = %d, want %d", a, want)
+	}
+
+	// 自定义 workerID 长度时截取为实例的长度
+	s, err := NewSnowflake(WithLen(41, 8, 14), WithAzureWorkerID(context.Background(), WithAzureIMDSEndpoint(srv.URL)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(h.Sum64() & (1<<8 - 1)); s.WorkerID() != want {
+		t.Errorf("worker id = %d, want %d", s.WorkerID(), want)
+	}
+}
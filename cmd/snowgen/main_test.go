@@ -0,0 +1,36 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	if code := run([]string{"--count=3", "--worker-id=5", "--format=hex"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("code = %d, stderr = %q", code, stderr.String())
+	}
+	if lines := strings.Fields(stdout.String()); len(lines) != 3 {
+		t.Errorf("got %d lines, want 3", len(lines))
+	}
+
+	stdout.Reset()
+	if code := run([]string{"--count=2", "--worker-id=5", "--format=json"}, &stdout, &stderr); code != 0 {
+		t.Fatalf("code = %d, stderr = %q", code, stderr.String())
+	}
+	var ids []string
+	if err := json.Unmarshal(stdout.Bytes(), &ids); err != nil || len(ids) != 2 {
+		t.Errorf("ids = %v, err = %v", ids, err)
+	}
+
+	stderr.Reset()
+	if code := run([]string{"--count=-1"}, &stdout, &stderr); code != 2 || !strings.Contains(stderr.String(), "Usage") {
+		t.Errorf("negative count: code = %d, stderr = %q", code, stderr.String())
+	}
+
+	if code := run([]string{"--worker-id=5", "--bit-seq=20"}, &stdout, &stderr); code != 1 {
+		t.Errorf("invalid layout: code = %d", code)
+	}
+}
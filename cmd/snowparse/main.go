@@ -0,0 +1,126 @@
+// snowparse 解析雪花算法生成的 id，输出生成时间、workerID 和序列号
+//
+// 用法：
+//
+//	snowparse [flags] [id ...]
+//
+// 没有传入 id 时从标准输入读取，每行一个
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/edte/snowflake"
+)
+
+// field 解析后的 id
+type field struct {
+	ID         int64  `json:"id,string"`
+	Time       string `json:"time"`
+	WorkerID   int64  `json:"worker_id"`
+	SequenceID int64  `json:"sequence_id"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run 执行命令，返回退出码
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("snowparse", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	epoch := fs.Int64("epoch", 0, "epoch in unix milliseconds")
+	bitTime := fs.Int64("bit-time", snowflake.DefaultBitLayout.Time, "time bits")
+	bitWorker := fs.Int64("bit-worker", snowflake.DefaultBitLayout.WorkerID, "worker id bits")
+	bitSeq := fs.Int64("bit-seq", snowflake.DefaultBitLayout.Sequence, "sequence bits")
+	nonIncrement := fs.Bool("non-increment", false, "ids are generated with WithNonIncrement")
+	format := fs.String("format", "text", "output format: text, json or csv")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	layout := snowflake.BitLayout{
+		Time:         *bitTime,
+		WorkerID:     *bitWorker,
+		Sequence:     *bitSeq,
+		NonIncrement: *nonIncrement,
+	}
+
+	var print func(f field) error
+	switch *format {
+	case "text":
+		print = func(f field) error {
+			_, err := fmt.Fprintf(stdout, "%d\t%s\tworker=%d\tseq=%d\n", f.ID, f.Time, f.WorkerID, f.SequenceID)
+			return err
+		}
+	case "json":
+		enc := json.NewEncoder(stdout)
+		print = func(f field) error {
+			return enc.Encode(f)
+		}
+	case "csv":
+		w := csv.NewWriter(stdout)
+		defer w.Flush()
+		_ = w.Write([]string{"id", "time", "worker_id", "sequence_id"})
+		print = func(f field) error {
+			return w.Write([]string{
+				strconv.FormatInt(f.ID, 10),
+				f.Time,
+				strconv.FormatInt(f.WorkerID, 10),
+				strconv.FormatInt(f.SequenceID, 10),
+			})
+		}
+	default:
+		fmt.Fprintf(stderr, "snowparse: unknown format %q\n", *format)
+		return 2
+	}
+
+	ids := fs.Args()
+	if len(ids) == 0 {
+		sc := bufio.NewScanner(stdin)
+		for sc.Scan() {
+			if line := strings.TrimSpace(sc.Text()); line != "" {
+				ids = append(ids, line)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			fmt.Fprintf(stderr, "snowparse: %v\n", err)
+			return 1
+		}
+	}
+
+	code := 0
+	for _, s := range ids {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			fmt.Fprintf(stderr, "snowparse: invalid id %q\n", s)
+			code = 1
+			continue
+		}
+
+		t, w, seq := layout.Decompose(id)
+		f := field{
+			ID:         id,
+			Time:       time.UnixMilli(t + *epoch).UTC().Format("2006-01-02T15:04:05.000Z07:00"),
+			WorkerID:   w,
+			SequenceID: seq,
+		}
+		if err = print(f); err != nil {
+			fmt.Fprintf(stderr, "snowparse: %v\n", err)
+			return 1
+		}
+	}
+
+	return code
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/edte/snowflake"
+)
+
+func TestRun(t *testing.T) {
+	id := snowflake.DefaultBitLayout.Compose(1000, 3, 7)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"--format=csv"}, strings.NewReader("12abc\n  1\n\n"), &stdout, &stderr)
+	if code != 1 || !strings.Contains(stderr.String(), "12abc") {
+		t.Errorf("code = %d, stderr = %q", code, stderr.String())
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	if code = run([]string{"--format=json", strconv.FormatInt(id, 10)}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("code = %d, stderr = %q", code, stderr.String())
+	}
+	want := `{"id":"` + strconv.FormatInt(id, 10) + `","time":"1970-01-01T00:00:01.000Z","worker_id":3,"sequence_id":7}` + "\n"
+	if stdout.String() != want {
+		t.Errorf("got %q, want %q", stdout.String(), want)
+	}
+
+	if code = run([]string{"--format=xml", "1"}, nil, &stdout, &stderr); code != 2 {
+		t.Errorf("unknown format: code = %d", code)
+	}
+}
@@ -0,0 +1,146 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"unicode/utf8"
+)
+
+// IDCodec id 编解码，用于把 id 转换成字符串形式，以及从字符串还原 id
+type IDCodec interface {
+	// Encode 编码 id
+	Encode(id int64) string
+	// Decode 解码字符串为 id
+	Decode(s string) (int64, error)
+}
+
+// Base62Alphabet base-62 的字符表
+const Base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Base62 base-62 编解码
+var Base62 IDCodec = mustAlphabetCodec(Base62Alphabet)
+
+// mustAlphabetCodec 同 NewAlphabetCodec，字符表不合法时 panic，只用于包内预定义的字符表
+func mustAlphabetCodec(alphabet string) IDCodec {
+	c, err := NewAlphabetCodec(alphabet)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// alphabetCodec 使用自定义字符表的 N 进制编解码
+type alphabetCodec struct {
+	// 字符表
+	alphabet []rune
+	// 字符在字符表中的位置
+	index map[rune]uint64
+}
+
+// NewAlphabetCodec 使用自定义的字符表创建一个 N 进制编解码，N 为字符表的长度
+// 字符表按字符（rune）计算，要求字符不能重复，且至少有 2 个，因此 emoji 也可以使用
+// id 按 uint64 编码，编码后的长度不固定，最长为 ceil(64/log2(N))，常见的字符表：
+// base-16 16 位，base-36 13 位，base-58、base-62 11 位
+func NewAlphabetCodec(alphabet string) (IDCodec, error) {
+	if !utf8.ValidString(alphabet) {
+		return nil, errors.New("snowflake: alphabet is not valid utf-8")
+	}
+
+	c := &alphabetCodec{
+		alphabet: []rune(alphabet),
+		index:    make(map[rune]uint64),
+	}
+	if len(c.alphabet) < 2 {
+		return nil, errors.New("snowflake: alphabet must contain at least 2 characters")
+	}
+
+	for i, r := range c.alphabet {
+		if _, ok := c.index[r]; ok {
+			return nil, fmt.Errorf("snowflake: duplicate character %q in alphabet", r)
+		}
+		c.index[r] = uint64(i)
+	}
+
+	return c, nil
+}
+
+func (c *alphabetCodec) Encode(id int64) string {
+	base := uint64(len(c.alphabet))
+	n := uint64(id)
+
+	if n == 0 {
+		return string(c.alphabet[0])
+	}
+
+	// 从低位到高位计算，最后再反转
+	var b []rune
+	for n > 0 {
+		b = append(b, c.alphabet[n%base])
+		n /= base
+	}
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+
+	return string(b)
+}
+
+func (c *alphabetCodec) Decode(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("snowflake: empty id string")
+	}
+
+	base := uint64(len(c.alphabet))
+
+	var n uint64
+	for _, r := range s {
+		d, ok := c.index[r]
+		if !ok {
+			return 0, fmt.Errorf("snowflake: invalid character %q in id %q", r, s)
+		}
+		if n > (math.MaxUint64-d)/base {
+			return 0, fmt.Errorf("snowflake: id %q overflows int64", s)
+		}
+		n = n*base + d
+	}
+
+	return int64(n), nil
+}
+
+// NextIDEncodings 生成一个 id，并按顺序用每一个 codec 编码，用于同一个 id 需要多种格式的场景
+// 比如日志使用十进制、存储使用 16 进制、HTTP 响应使用 base-62
+func (s *Snowflake) NextIDEncodings(codecs ...IDCodec) (id int64, encodings []string, err error) {
+	if id, err = s.NextID(); err != nil {
+		return 0, nil, err
+	}
+
+	encodings = make([]string, len(codecs))
+	for i, c := range codecs {
+		encodings[i] = c.Encode(id)
+	}
+
+	return id, encodings, nil
+}
+
+// NextNIDs 在一次加锁内生成 n 个 id，并用 codec 编码，用于 HTTP 响应等只需要字符串的场景
+// 不需要先生成 []int64 再逐个编码
+func (s *Snowflake) NextNIDs(n int, codec IDCodec) ([]string, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("snowflake: invalid count %d", n)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	res := make([]string, n)
+	for i := range res {
+		id, err := s.nextID()
+		if err != nil {
+			return nil, err
+		}
+		res[i] = codec.Encode(id)
+	}
+
+	return res, nil
+}
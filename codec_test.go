@@ -0,0 +1,114 @@
+package snowflake
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNewAlphabetCodec(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	alphabets := []string{
+		"01",
+		"0123456789abcdefghijklmnopqrstuvwxyz",
+		"123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz",
+		"🍎🍌🍒🍇",
+	}
+
+	for _, alphabet := range alphabets {
+		c, err := NewAlphabetCodec(alphabet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ids := []int64{0, 1, math.MinInt64, math.MaxInt64, -1}
+		for i := 0; i < 10; i++ {
+			ids = append(ids, next(t, s))
+		}
+
+		for _, id := range ids {
+			got, err := c.Decode(c.Encode(id))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != id {
+				t.Errorf("alphabet %q: round trip %d got %d", alphabet, id, got)
+			}
+		}
+	}
+}
+
+func TestNewAlphabetCodecInvalid(t *testing.T) {
+	for _, alphabet := range []string{"", "a", "abca"} {
+		if _, err := NewAlphabetCodec(alphabet); err == nil {
+			t.Errorf("alphabet %q: expected error", alphabet)
+		}
+	}
+
+	c, _ := NewAlphabetCodec("01")
+	if _, err := c.Decode("012"); err == nil {
+		t.Error("expected error for invalid character")
+	}
+	if _, err := c.Decode("11111111111111111111111111111111111111111111111111111111111111111"); err == nil {
+		t.Error("expected overflow error")
+	}
+}
+
+func TestNextIDEncodings(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	hex, err := NewAlphabetCodec("0123456789abcdef")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, encodings, err := s.NextIDEncodings(Base62, hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(encodings) != 2 {
+		t.Fatalf("got %d encodings, want 2", len(encodings))
+	}
+	for i, c := range []IDCodec{Base62, hex} {
+		if got, err := c.Decode(encodings[i]); err != nil || got != id {
+			t.Errorf("encoding %d: decoded %d, %v, want %d", i, got, err, id)
+		}
+	}
+}
+
+func TestNextNIDs(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	res, err := s.NextNIDs(100, Base62)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 100 {
+		t.Fatalf("got %d ids, want 100", len(res))
+	}
+
+	var prev int64
+	for _, str := range res {
+		id, err := Base62.Decode(str)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id <= prev {
+			t.Fatalf("id %d not greater than %d", id, prev)
+		}
+		prev = id
+	}
+
+	if _, err = s.NextNIDs(-1, Base62); err == nil {
+		t.Error("expected error for negative count")
+	}
+}
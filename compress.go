@@ -0,0 +1,187 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// Compress 使用差值 + varint 编码压缩一批 id
+// 结构为：id 个数、第一个 id、之后每个 id 和前一个 id 的差值，都使用 varint 编码
+// 差值使用 zigzag 编码，因此输入不要求有序，但有序时压缩率最高，同一毫秒内的 id 差值只占 1 个字节
+func Compress(ids []int64) []byte {
+	buf := make([]byte, 0, binary.MaxVarintLen64+len(ids)*2)
+	buf = appendUvarint(buf, uint64(len(ids)))
+
+	var prev int64
+	for _, id := range ids {
+		buf = appendVarint(buf, id-prev)
+		prev = id
+	}
+
+	return buf
+}
+
+// Decompress 解压 Compress 压缩的 id
+func Decompress(data []byte) ([]int64, error) {
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(data)) {
+		return nil, fmt.Errorf("snowflake: compressed data too short for %d ids", n)
+	}
+
+	ids := make([]int64, n)
+
+	var prev int64
+	for i := range ids {
+		d, l := binary.Varint(data)
+		if l <= 0 {
+			return nil, errors.New("snowflake: invalid compressed data")
+		}
+		data = data[l:]
+		prev += d
+		ids[i] = prev
+	}
+
+	if len(data) != 0 {
+		return nil, errors.New("snowflake: trailing bytes after compressed data")
+	}
+
+	return ids, nil
+}
+
+// CompressParallel 使用 workers 个 goroutine 并行压缩一批 id，适用于很大的切片
+// 把 ids 分成最多 workers 块，每块分别使用 Compress 压缩，然后拼接起来，结构为：
+// 块的个数、每一块的长度和内容，都使用 varint 编码长度
+func (s *Snowflake) CompressParallel(ids []int64, workers int) []byte {
+	if workers < 1 {
+		workers = 1
+	}
+
+	// 按照每块的长度计算块的个数，不能整除时最后几块会是空的，不能直接使用 workers
+	size := (len(ids) + workers - 1) / workers
+	n := 0
+	if size > 0 {
+		n = (len(ids) + size - 1) / size
+	}
+	chunks := make([][]byte, n)
+
+	var wg sync.WaitGroup
+	for i := range chunks {
+		lo, hi := i*size, (i+1)*size
+		if hi > len(ids) {
+			hi = len(ids)
+		}
+
+		wg.Add(1)
+		go func(i int, part []int64) {
+			defer wg.Done()
+			chunks[i] = Compress(part)
+		}(i, ids[lo:hi])
+	}
+	wg.Wait()
+
+	total := binary.MaxVarintLen64
+	for _, c := range chunks {
+		total += binary.MaxVarintLen64 + len(c)
+	}
+
+	buf := make([]byte, 0, total)
+	buf = appendUvarint(buf, uint64(len(chunks)))
+	for _, c := range chunks {
+		buf = appendUvarint(buf, uint64(len(c)))
+		buf = append(buf, c...)
+	}
+
+	return buf
+}
+
+// DecompressParallel 使用 workers 个 goroutine 并行解压 CompressParallel 压缩的 id
+func (s *Snowflake) DecompressParallel(data []byte, workers int) ([]int64, error) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	n, data, err := readUvarint(data)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(len(data)) {
+		return nil, fmt.Errorf("snowflake: compressed data too short for %d chunks", n)
+	}
+
+	chunks := make([][]byte, n)
+	for i := range chunks {
+		var l uint64
+		if l, data, err = readUvarint(data); err != nil {
+			return nil, err
+		}
+		if l > uint64(len(data)) {
+			return nil, errors.New("snowflake: compressed chunk out of range")
+		}
+		chunks[i], data = data[:l], data[l:]
+	}
+	if len(data) != 0 {
+		return nil, errors.New("snowflake: trailing bytes after compressed data")
+	}
+
+	results := make([][]int64, n)
+	errs := make([]error, n)
+
+	// 使用 workers 个 goroutine 处理所有的块
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = Decompress(chunks[i])
+			}
+		}()
+	}
+	for i := range chunks {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	total := 0
+	for i := range results {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		total += len(results[i])
+	}
+
+	ids := make([]int64, 0, total)
+	for _, r := range results {
+		ids = append(ids, r...)
+	}
+
+	return ids, nil
+}
+
+// readUvarint 读取一个 varint 编码的无符号整数，返回剩余的数据
+func readUvarint(data []byte) (uint64, []byte, error) {
+	v, l := binary.Uvarint(data)
+	if l <= 0 {
+		return 0, nil, errors.New("snowflake: invalid compressed data")
+	}
+	return v, data[l:], nil
+}
+
+// appendUvarint 把 varint 编码的无符号整数追加到 buf
+func appendUvarint(buf []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+// appendVarint 把 zigzag varint 编码的整数追加到 buf
+func appendVarint(buf []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(buf, b[:binary.PutVarint(b[:], v)]...)
+}
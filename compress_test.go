@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"reflect"
+	"runtime"
+	"strconv"
+	"testing"
+)
+
+// sortedIDs 生成 n 个有序的 id，模拟每毫秒生成 100 个 id
+func sortedIDs(n int) []int64 {
+	ids := make([]int64, n)
+	base := int64(1) << 62
+	for i := range ids {
+		ids[i] = base + int64(i/100)<<22 + int64(i%100)
+	}
+	return ids
+}
+
+func TestCompress(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	cases := [][]int64{nil, {0}, {-1, 1, -1 << 63, 1<<63 - 1}, sortedIDs(10), sortedIDs(1000)}
+	var ids []int64
+	for i := 0; i < 100; i++ {
+		ids = append(ids, next(t, s))
+	}
+	cases = append(cases, ids)
+
+	for _, c := range cases {
+		got, err := Decompress(Compress(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != len(c) || (len(c) > 0 && !reflect.DeepEqual(got, c)) {
+			t.Errorf("round trip %v got %v", c, got)
+		}
+
+		// 7 个 worker 处理 10 个 id 时不能整除
+		for _, workers := range []int{1, 3, 7, 8} {
+			got, err = s.DecompressParallel(s.CompressParallel(c, workers), workers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(c) || (len(c) > 0 && !reflect.DeepEqual(got, c)) {
+				t.Errorf("parallel round trip with %d workers: %v got %v", workers, c, got)
+			}
+		}
+	}
+
+	if _, err = Decompress([]byte{5, 1}); err == nil {
+		t.Error("expected error for truncated data")
+	}
+}
+
+func BenchmarkCompress(b *testing.B) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, n := range []int{1e6, 1e7, 1e8} {
+		if n == 1e8 && testing.Short() {
+			continue
+		}
+		ids := sortedIDs(n)
+
+		b.Run("sequential/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				Compress(ids)
+			}
+		})
+		b.Run("parallel/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				s.CompressParallel(ids, runtime.NumCPU())
+			}
+		})
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, n := range []int{1e6, 1e7, 1e8} {
+		if n == 1e8 && testing.Short() {
+			continue
+		}
+		ids := sortedIDs(n)
+		data := Compress(ids)
+		parallel := s.CompressParallel(ids, runtime.NumCPU())
+
+		b.Run("sequential/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = Decompress(data)
+			}
+		})
+		b.Run("parallel/"+strconv.Itoa(n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = s.DecompressParallel(parallel, runtime.NumCPU())
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package snowflake
+
+// NextIDIf 在 condition 返回 true 时才生成 id，condition 的判断和 id 的生成在同一次加锁中完成
+// 比如“只有计数小于上限时才生成”，condition 返回 false 时返回 0, false
+// 注意 condition 在持有锁的情况下调用，不能再调用本实例的 NextID 等方法，否则会死锁
+func (s *Snowflake) NextIDIf(condition func() bool) (id int64, generated bool, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if !condition() {
+		return 0, false, nil
+	}
+
+	if id, err = s.nextID(); err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
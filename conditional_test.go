@@ -0,0 +1,27 @@
+package snowflake
+
+import "testing"
+
+func TestNextIDIf(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	count := 0
+	for i := 0; i < 5; i++ {
+		id, ok, err := s.NextIDIf(func() bool { return count < 3 })
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok {
+			count++
+		} else if id != 0 {
+			t.Errorf("id = %d, want 0 when not generated", id)
+		}
+	}
+
+	if count != 3 {
+		t.Errorf("generated %d ids, want 3", count)
+	}
+}
@@ -0,0 +1,32 @@
+package snowflake
+
+import "errors"
+
+// NextCursorPage 生成 lastSeenID 之后的一页 id，用于游标分页
+// 和 NextIDAfter(TimeFromID(lastSeenID)) 一样，lastSeenID 的时间比当前时间晚时会等待时钟追上，
+// 再连续生成 pageSize 个 id，结果唯一、单调递增，并且都大于 lastSeenID
+// 整页在一次加锁内生成，中间不会插入其它 goroutine 的 id
+func (s *Snowflake) NextCursorPage(lastSeenID int64, pageSize int) ([]int64, error) {
+	if pageSize <= 0 {
+		return nil, errors.New("snowflake: page size must be positive")
+	}
+	if s.descending {
+		return nil, errors.New("snowflake: cursor pages require ascending ids")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make([]int64, 0, pageSize)
+	prev := lastSeenID
+	for len(ids) < pageSize {
+		id, err := s.nextIDGreaterThan(prev)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+		prev = id
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextCursorPage(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	// 上一页的最后一个 id 来自一台快 20ms 的机器
+	fast, err := NewSnowflake(WithClock(func() int64 {
+		return time.Now().UnixMilli() + 20
+	}))
+	if err != nil {
+		panic(err)
+	}
+	last := next(t, fast)
+
+	ids, err := s.NextCursorPage(last, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 100 {
+		t.Fatalf("got %d ids, want 100", len(ids))
+	}
+
+	prev := last
+	for _, id := range ids {
+		if id <= prev {
+			t.Fatalf("id %d not greater than %d", id, prev)
+		}
+		prev = id
+	}
+
+	if _, err = s.NextCursorPage(last, 0); err == nil {
+		t.Error("expected error for empty page")
+	}
+}
@@ -0,0 +1,45 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// dynamoKeySeparator 实体类型和 id 之间的分隔符
+const dynamoKeySeparator = "#"
+
+// NextDynamoKey 生成一个 DynamoDB 分区键，格式为 "<entityType>#<base62 id>"
+// id 每毫秒都在变化，同一秒内的写入会被哈希到不同的分区，避免热点分区
+//
+// 配合 DynamoDB SDK 使用：
+//
+//	key, err := sf.NextDynamoKey("order")
+//	if err != nil {
+//		return err
+//	}
+//	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+//		TableName: aws.String("orders"),
+//		Item: map[string]types.AttributeValue{
+//			"pk": &types.AttributeValueMemberS{Value: key},
+//		},
+//	})
+func (s *Snowflake) NextDynamoKey(entityType string) (string, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return "", err
+	}
+	return entityType + dynamoKeySeparator + Base62.Encode(id), nil
+}
+
+// ParseDynamoKey 解析 NextDynamoKey 生成的分区键，返回实体类型和 id
+// 实体类型中可以包含分隔符，以最后一个分隔符为准
+func ParseDynamoKey(key string) (entityType string, id int64, err error) {
+	i := strings.LastIndex(key, dynamoKeySeparator)
+	if i < 0 {
+		return "", 0, fmt.Errorf("snowflake: invalid dynamo key %q", key)
+	}
+	if id, err = Base62.Decode(key[i+1:]); err != nil {
+		return "", 0, err
+	}
+	return key[:i], id, nil
+}
@@ -0,0 +1,31 @@
+package snowflake
+
+import "testing"
+
+func TestNextDynamoKey(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entity := range []string{"order", "user#profile", ""} {
+		key, err := s.NextDynamoKey(entity)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		gotEntity, id, err := ParseDynamoKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotEntity != entity || id != s.LastID() {
+			t.Errorf("%q: got (%q, %d), want (%q, %d)", key, gotEntity, id, entity, s.LastID())
+		}
+	}
+
+	for _, bad := range []string{"order", "order#", "order#!!"} {
+		if _, _, err = ParseDynamoKey(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
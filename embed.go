@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// nextIDEmbedding 生成一个 id，并把 value 放到序列号的高 bits 位，调用方需要持有锁
+// 这一毫秒的计数超过了剩下的低位能表示的范围时，会等待下一毫秒，保证同一个实例生成的这类 id 不重复，
+// 因此每毫秒最多生成 2^(序列号长度-bits) 个
+// 注意和普通的 NextID 混用时，普通 id 的序列号高位可能和 value 相同，建议使用单独的实例
+func (s *Snowflake) nextIDEmbedding(bits, value int64) (int64, error) {
+	if s.tagBits+s.priorityBits+s.checksumBits > 0 {
+		return 0, errors.New("snowflake: cannot embed values together with WithTypeTag, WithPriorityBits or WithChecksumBits")
+	}
+	if s.descending {
+		return 0, errors.New("snowflake: cannot embed values together with WithDescendingSequence")
+	}
+	// 序列号不从 0 开始时，第一个序列号就可能用到高位，永远等不到可用的低位
+	if s.sequenceOffset != 0 || s.entropy != nil {
+		return 0, errors.New("snowflake: cannot embed values together with WithReplicaSequenceOffset or WithEntropySource")
+	}
+	if bits >= s.bitLenSequence {
+		return 0, fmt.Errorf("snowflake: %d bits do not fit in %d sequence bits", bits, s.bitLenSequence)
+	}
+	if value < 0 || value >= 1<<bits {
+		return 0, fmt.Errorf("snowflake: value %d does not fit in %d bits", value, bits)
+	}
+
+	shift := s.bitLenSequence - bits
+	deadline := s.clock() + s.maxWait.Milliseconds()
+
+	for {
+		id, err := s.nextID()
+		if err != nil {
+			return 0, err
+		}
+
+		t, w, seq := s.decompose(id)
+		if seq < 1<<shift {
+			return s.compose(t, w, value<<shift|seq), nil
+		}
+
+		// 这一毫秒剩下的低位不够用了，等待下一毫秒
+		if s.clock() > deadline {
+			return 0, ErrMaxWaitExceeded
+		}
+		for s.clock() <= s.lastTime {
+		}
+	}
+}
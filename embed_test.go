@@ -0,0 +1,34 @@
+package snowflake
+
+import (
+	"crypto/rand"
+	"testing"
+)
+
+func TestNextIDEmbeddingRejected(t *testing.T) {
+	cases := []struct {
+		name string
+		opt  Option
+	}{
+		{"replica offset", WithReplicaSequenceOffset(100)},
+		{"entropy", WithEntropySource(rand.Reader)},
+		{"descending", WithDescendingSequence()},
+		{"checksum", WithChecksumBits(2)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSnowflake(c.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err = s.NextTaskID(100); err == nil {
+				t.Error("NextTaskID: expected error")
+			}
+			if _, err = s.NextStateTransitionID(1, 2); err == nil {
+				t.Error("NextStateTransitionID: expected error")
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package snowflake
+
+import "testing"
+
+func TestNextIDForEntity(t *testing.T) {
+	s, err := NewSnowflake(WithEntityBits(3, 8))
+	if err != nil {
+		panic(err)
+	}
+
+	id, err := s.NextIDForEntity(2, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind, entityID := ParseEntityID(id, s.Layout(), 3, 8); kind != 2 || entityID != 200 {
+		t.Errorf("got (%d, %d), want (2, 200)", kind, entityID)
+	}
+
+	if _, err = s.NextIDForEntity(8, 0); err == nil {
+		t.Error("expected error for kind out of range")
+	}
+	if _, err = s.NextIDForEntity(1, 256); err == nil {
+		t.Error("expected error for entity id out of range")
+	}
+
+	plain, _ := NewSnowflake()
+	if _, err = plain.NextIDForEntity(1, 1); err == nil {
+		t.Error("expected error without entity bits")
+	}
+	if _, err = NewSnowflake(WithEntityBits(4, 9)); err == nil {
+		t.Error("expected error for entity bits wider than the worker field")
+	}
+}
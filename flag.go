@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// FeatureFlagBucket 计算用户在 A/B 测试中的分桶，同一个 userID 和 numBuckets 总是得到同一个分桶
+// 只使用 userID 的 workerID 和序列号部分计算 FNV-1a，不使用时间部分
+// numBuckets 小于等于 0 时返回 -1
+func (s *Snowflake) FeatureFlagBucket(userID int64, numBuckets int) int {
+	if numBuckets <= 0 {
+		return -1
+	}
+
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], s.stableKey(userID))
+
+	h := fnv.New64a()
+	_, _ = h.Write(b[:])
+
+	return int(h.Sum64() % uint64(numBuckets))
+}
+
+// FeatureFlagEnabled 判断用户是否在 rolloutPercent% 的灰度范围内，是 FeatureFlagBucket 的简单封装
+// 提高 rolloutPercent 时，之前已经在范围内的用户仍然在范围内
+func (s *Snowflake) FeatureFlagEnabled(userID int64, rolloutPercent int) bool {
+	return s.FeatureFlagBucket(userID, 100) < rolloutPercent
+}
+
+// ExperimentBucket 计算用户在实验中的分桶，和 FeatureFlagBucket 一样只使用 workerID 和序列号部分，
+// 避免随着时间推移用户换桶，区别是使用 splitmix64 的混合函数代替 FNV-1a，
+// 因此同一个用户在实验和功能开关中的分桶是相互独立的
+// numBuckets 小于等于 0 时返回 -1
+func (s *Snowflake) ExperimentBucket(userID int64, numBuckets int) int {
+	if numBuckets <= 0 {
+		return -1
+	}
+	return int(mix64(s.stableKey(userID)) % uint64(numBuckets))
+}
+
+// ExperimentEnabled 判断用户是否在 rolloutPercent% 的实验范围内，是 ExperimentBucket 的简单封装
+func (s *Snowflake) ExperimentEnabled(userID int64, rolloutPercent int) bool {
+	return s.ExperimentBucket(userID, 100) < rolloutPercent
+}
+
+// stableKey 取出 id 中不随时间变化的部分，即 workerID 和序列号
+func (s *Snowflake) stableKey(id int64) uint64 {
+	_, w, seq := s.decompose(id)
+	return uint64(w<<s.bitLenSequence | seq)
+}
+
+// mix64 splitmix64 的混合函数，把相近的输入打散到整个 uint64 范围
+func mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
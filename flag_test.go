@@ -0,0 +1,62 @@
+package snowflake
+
+import "testing"
+
+func TestFeatureFlagBucket(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	enabled := 0
+	for i := 0; i < 1000; i++ {
+		id := next(t, s)
+
+		b := s.FeatureFlagBucket(id, 10)
+		if b < 0 || b >= 10 || b != s.FeatureFlagBucket(id, 10) {
+			t.Fatalf("bucket %d is invalid or unstable", b)
+		}
+
+		if s.FeatureFlagEnabled(id, 30) {
+			enabled++
+			if !s.FeatureFlagEnabled(id, 50) {
+				t.Fatal("raising the rollout should keep enabled users")
+			}
+		}
+	}
+
+	if enabled < 200 || enabled > 400 {
+		t.Errorf("%d of 1000 users enabled at 30%%", enabled)
+	}
+	if s.FeatureFlagBucket(1, 0) != -1 {
+		t.Error("expected -1 for zero buckets")
+	}
+}
+
+func TestExperimentBucket(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	counts := make([]int, 4)
+	for i := 0; i < 4000; i++ {
+		id := next(t, s)
+
+		b := s.ExperimentBucket(id, 4)
+		if b < 0 || b >= 4 || b != s.ExperimentBucket(id, 4) {
+			t.Fatalf("bucket %d is invalid or unstable", b)
+		}
+		counts[b]++
+
+		if s.ExperimentEnabled(id, 10) && !s.ExperimentEnabled(id, 20) {
+			t.Fatal("raising the rollout should keep enabled users")
+		}
+	}
+
+	for i, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Errorf("bucket %d has %d of 4000 users", i, c)
+		}
+	}
+}
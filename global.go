@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"sync"
+)
+
+var (
+	// 全局默认配置的锁
+	defaultOptionsMutex sync.RWMutex
+	// 全局默认配置，NewSnowflake 会在用户的配置之前应用
+	defaultOptions []Option
+
+	// 全局 epoch 和 id 结构的锁
+	globalMutex sync.RWMutex
+	// 全局 epoch，没有使用 WithEpoch 时的默认值
+	globalEpoch int64
+	// 全局 id 结构，没有使用 WithLen、WithNonIncrement 时的默认值
+	globalBitLayout = DefaultBitLayout
+)
+
+// RegisterDefaultOption 注册一个全局默认配置，之后所有 NewSnowflake 创建的实例都会应用这个配置
+// 默认配置在用户传入的配置之前应用，因此可以被覆盖
+// 注意这是一个包级别的全局副作用，只应该在框架的初始化代码中调用
+func RegisterDefaultOption(opt Option) {
+	defaultOptionsMutex.Lock()
+	defer defaultOptionsMutex.Unlock()
+
+	defaultOptions = append(defaultOptions, opt)
+}
+
+// UnregisterDefaultOptions 清除所有注册的全局默认配置
+func UnregisterDefaultOptions() {
+	defaultOptionsMutex.Lock()
+	defer defaultOptionsMutex.Unlock()
+
+	defaultOptions = nil
+}
+
+// registeredOptions 返回当前注册的全局默认配置
+func registeredOptions() []Option {
+	defaultOptionsMutex.RLock()
+	defer defaultOptionsMutex.RUnlock()
+
+	return append([]Option(nil), defaultOptions...)
+}
+
+// SetGlobalEpoch 设置全局 epoch，之后 NewSnowflake 创建的实例在没有使用 WithEpoch 时都使用这个 epoch
+// 用于进程内所有实例共享同一个自定义 epoch 的场景，不影响已经创建的实例
+func SetGlobalEpoch(epochMs int64) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+
+	globalEpoch = epochMs
+}
+
+// GlobalEpoch 返回全局 epoch，默认为 0，即 Unix 时间
+func GlobalEpoch() int64 {
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+
+	return globalEpoch
+}
+
+// SetGlobalBitLayout 设置全局 id 结构，之后 NewSnowflake 创建的实例在没有使用 WithLen、WithNonIncrement 时都使用这个结构
+// 结构不合法时由 NewSnowflake 返回错误
+func SetGlobalBitLayout(layout BitLayout) {
+	globalMutex.Lock()
+	defer globalMutex.Unlock()
+
+	globalBitLayout = layout
+}
+
+// globalLayout 返回全局 id 结构
+func globalLayout() BitLayout {
+	globalMutex.RLock()
+	defer globalMutex.RUnlock()
+
+	return globalBitLayout
+}
@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRegisterDefaultOption(t *testing.T) {
+	defer UnregisterDefaultOptions()
+
+	epoch := time.Now().Add(-time.Hour).UnixMilli()
+	RegisterDefaultOption(WithEpoch(epoch))
+
+	s, err := NewSnowflake()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() != epoch {
+		t.Errorf("epoch = %d, want %d", s.Epoch(), epoch)
+	}
+
+	// 用户配置覆盖默认配置
+	s, err = NewSnowflake(WithEpoch(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() != 0 {
+		t.Errorf("epoch = %d, want 0", s.Epoch())
+	}
+
+	UnregisterDefaultOptions()
+	s, err = NewSnowflake()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() != 0 {
+		t.Errorf("epoch = %d after unregister, want 0", s.Epoch())
+	}
+}
+
+func TestSetGlobalEpoch(t *testing.T) {
+	defer SetGlobalEpoch(0)
+
+	epoch := time.Now().Add(-time.Hour).UnixMilli()
+	SetGlobalEpoch(epoch)
+	if GlobalEpoch() != epoch {
+		t.Errorf("global epoch = %d, want %d", GlobalEpoch(), epoch)
+	}
+
+	s, err := NewSnowflake()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() != epoch {
+		t.Errorf("epoch = %d, want %d", s.Epoch(), epoch)
+	}
+
+	// WithEpoch 覆盖全局 epoch
+	if s, err = NewSnowflake(WithEpoch(0)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Epoch() != 0 {
+		t.Errorf("epoch = %d, want 0", s.Epoch())
+	}
+}
+
+func TestSetGlobalBitLayout(t *testing.T) {
+	defer SetGlobalBitLayout(DefaultBitLayout)
+
+	layout := BitLayout{Time: 41, WorkerID: 14, Sequence: 8, NonIncrement: true}
+	SetGlobalBitLayout(layout)
+
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 1, nil }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Layout() != layout {
+		t.Errorf("layout = %+v, want %+v", s.Layout(), layout)
+	}
+	if s.SequenceMask() != 1<<8-1 {
+		t.Errorf("sequence mask = %d, want %d", s.SequenceMask(), 1<<8-1)
+	}
+
+	SetGlobalBitLayout(BitLayout{Time: 41, WorkerID: 10, Sequence: 10})
+	if _, err = NewSnowflake(); err == nil {
+		t.Error("expected error for invalid global layout")
+	}
+}
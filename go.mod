@@ -1,3 +1,5 @@
 module github.com/edte/snowflake
 
-go 1.18
+go 1.22
+
+require google.golang.org/protobuf v1.36.6
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithGroupBits 在 workerID 部分的高 n 位预留分组标签，用于 NextIDGroup
+// 把事件路由到不同的处理组（比如快车道、慢车道），机器 id 放在剩下的低位，必须能放进低位
+// 要求 n <= 8 且 n < bitLenWorkerID，不能和 WithRegionID 同时使用，否则 NewSnowflake 返回错误
+func WithGroupBits(n int) Option {
+	return func(s *Snowflake) {
+		s.groupBits = int64(n)
+	}
+}
+
+// NextIDGroup 生成一个带分组标签的 id，workerID 部分的高位为 groupTag，低位为机器 id
+// 普通的 NextID 相当于分组 0，需要先通过 WithGroupBits 设置分组标签的长度
+func (s *Snowflake) NextIDGroup(groupTag uint8) (int64, error) {
+	if s.groupBits == 0 {
+		return 0, errors.New("snowflake: group bits not configured, use WithGroupBits")
+	}
+	if int64(groupTag) >= 1<<s.groupBits {
+		return 0, fmt.Errorf("snowflake: group tag %d does not fit in %d bits", groupTag, s.groupBits)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return 0, err
+	}
+
+	t, w, seq := s.decompose(id)
+	return s.compose(t, int64(groupTag)<<(s.bitLenWorkerID-s.groupBits)|w, seq), nil
+}
+
+// GroupFromID 取出 NextIDGroup 设置的分组标签，即 workerID 部分的高 groupBits 位
+func GroupFromID(id int64, layout BitLayout, groupBits int) uint8 {
+	_, w, _ := layout.Decompose(id)
+	return uint8(w >> (layout.WorkerID - int64(groupBits)))
+}
@@ -0,0 +1,47 @@
+package snowflake
+
+import "testing"
+
+func TestNextIDGroup(t *testing.T) {
+	machine := WithWorkID(func() (int64, error) { return 5, nil })
+
+	s, err := NewSnowflake(machine, WithGroupBits(2))
+	if err != nil {
+		panic(err)
+	}
+
+	for tag := uint8(0); tag < 4; tag++ {
+		id, err := s.NextIDGroup(tag)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := GroupFromID(id, s.Layout(), 2); got != tag {
+			t.Errorf("group = %d, want %d", got, tag)
+		}
+		if _, w, _ := s.decompose(id); w&(1<<(s.bitLenWorkerID-2)-1) != 5 {
+			t.Errorf("machine id = %d, want 5", w&(1<<(s.bitLenWorkerID-2)-1))
+		}
+	}
+
+	if GroupFromID(next(t, s), s.Layout(), 2) != 0 {
+		t.Error("plain ids should be in group 0")
+	}
+	if _, err = s.NextIDGroup(4); err == nil {
+		t.Error("expected error for group tag out of range")
+	}
+
+	plain, _ := NewSnowflake(machine)
+	if _, err = plain.NextIDGroup(1); err == nil {
+		t.Error("expected error without group bits")
+	}
+
+	for _, opts := range [][]Option{
+		{machine, WithGroupBits(9)},
+		{machine, WithGroupBits(2), WithRegionID(1, 2)},
+		{WithWorkID(func() (int64, error) { return 1 << 11, nil }), WithGroupBits(2)},
+	} {
+		if _, err = NewSnowflake(opts...); err == nil {
+			t.Error("expected error for invalid group configuration")
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"fmt"
+)
+
+// hierarchyBits 层级占用序列号的高位数
+const hierarchyBits = 2
+
+// HierarchicalSnowflake 带层级的雪花算法，把层级放在序列号的高 2 位
+// 用于组织、部门、项目、文件这样的资源层级，只看 id 就能区分实体的层级，最多 4 级
+// 代价是每毫秒可用的序列号减少为 1/4
+type HierarchicalSnowflake struct {
+	sf *Snowflake
+}
+
+// NewHierarchical 新建一个带层级的雪花算法，配置和 NewSnowflake 一样，不能再设置 WithTypeTag
+func NewHierarchical(opts ...Option) (*HierarchicalSnowflake, error) {
+	sf, err := NewSnowflake(append(opts[:len(opts):len(opts)], WithTypeTag(0, hierarchyBits))...)
+	if err != nil {
+		return nil, err
+	}
+	return &HierarchicalSnowflake{sf: sf}, nil
+}
+
+// NextID 生成一个 level 层级的 id，level 为 0 到 3
+// 各个层级共用同一个计数，因此不同层级的 id 也不会重复
+func (h *HierarchicalSnowflake) NextID(level uint8) (int64, error) {
+	if level >= 1<<hierarchyBits {
+		return 0, fmt.Errorf("snowflake: level %d does not fit in %d bits", level, hierarchyBits)
+	}
+
+	h.sf.mutex.Lock()
+	defer h.sf.mutex.Unlock()
+
+	h.sf.tag = int64(level)
+	return h.sf.nextID()
+}
+
+// ParseHierarchicalID 取出 id 的层级，coreID 为去掉层级之后的 id
+func ParseHierarchicalID(id int64, layout BitLayout) (level uint8, coreID int64) {
+	t, w, seq := layout.Decompose(id)
+
+	shift := layout.Sequence - hierarchyBits
+	level = uint8(seq >> shift)
+
+	return level, layout.Compose(t, w, seq&(1<<shift-1))
+}
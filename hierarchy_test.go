@@ -0,0 +1,35 @@
+package snowflake
+
+import "testing"
+
+func TestHierarchicalSnowflake(t *testing.T) {
+	h, err := NewHierarchical()
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		level := uint8(i % 4)
+		id, err := h.NextID(level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+
+		got, core := ParseHierarchicalID(id, h.sf.Layout())
+		if got != level {
+			t.Fatalf("level = %d, want %d", got, level)
+		}
+		if l, _ := ParseHierarchicalID(core, h.sf.Layout()); l != 0 {
+			t.Fatal("core id should have no level")
+		}
+	}
+
+	if _, err = h.NextID(4); err == nil {
+		t.Error("expected error for level out of range")
+	}
+}
@@ -0,0 +1,44 @@
+//go:build go1.23
+
+package snowflake
+
+import (
+	"context"
+	"iter"
+)
+
+// NextIDSequence 返回一个依次生成 id 的迭代器，用于 Go 1.23 的 range over func：
+//
+//	for id := range sf.NextIDSequence(ctx) {
+//		...
+//	}
+//
+// ctx 取消、循环提前结束或者生成 id 失败（比如时间回拨）时停止，需要知道失败原因时请直接使用 NextID
+func (s *Snowflake) NextIDSequence(ctx context.Context) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for ctx.Err() == nil {
+			id, err := s.NextID()
+			if err != nil || !yield(id) {
+				return
+			}
+		}
+	}
+}
+
+// NextIDSequenceN 返回一个生成前 n 个 id 的迭代器，同时给出序号，从 0 开始：
+//
+//	for i, id := range sf.NextIDSequenceN(ctx, 100) {
+//		...
+//	}
+//
+// 和 NextIDSequence 一样，ctx 取消、循环提前结束或者生成 id 失败时提前停止
+func (s *Snowflake) NextIDSequenceN(ctx context.Context, n int) iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		for i := 0; i < n && ctx.Err() == nil; i++ {
+			id, err := s.NextID()
+			if err != nil || !yield(i, id) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+//go:build go1.23
+
+package snowflake
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNextIDSequence(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	var ids []int64
+	for id := range s.NextIDSequence(context.Background()) {
+		ids = append(ids, id)
+		if len(ids) == 100 {
+			break
+		}
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("id %d not greater than %d", ids[i], ids[i-1])
+		}
+	}
+
+	// ctx 取消后停止
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	n := 0
+	for range s.NextIDSequence(ctx) {
+		n++
+		if n == 10 {
+			cancel()
+		}
+	}
+	if n != 10 {
+		t.Errorf("got %d ids after cancel, want 10", n)
+	}
+}
+
+func TestNextIDSequenceN(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	n := 0
+	var prev int64
+	for i, id := range s.NextIDSequenceN(context.Background(), 100) {
+		if i != n {
+			t.Fatalf("index = %d, want %d", i, n)
+		}
+		if id <= prev {
+			t.Fatalf("id %d not greater than %d", id, prev)
+		}
+		prev = id
+		n++
+	}
+	if n != 100 {
+		t.Errorf("got %d ids, want 100", n)
+	}
+
+	for range s.NextIDSequenceN(context.Background(), 0) {
+		t.Fatal("expected no ids")
+	}
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// NextKafkaKey 生成一个 id，返回 8 字节大端序的表示，用作 Kafka 消息的 key
+// 大端序保证时间相近的 id 的前缀相同，比如使用 sarama：
+//
+//	key, err := sf.NextKafkaKey()
+//	if err != nil {
+//		return err
+//	}
+//	_, _, err = producer.SendMessage(&sarama.ProducerMessage{
+//		Topic: "events",
+//		Key:   sarama.ByteEncoder(key),
+//		Value: sarama.ByteEncoder(payload),
+//	})
+func (s *Snowflake) NextKafkaKey() ([]byte, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(id))
+
+	return b, nil
+}
+
+// KafkaKeyToID 把 NextKafkaKey 生成的 key 转换回 id
+func KafkaKeyToID(b []byte) (int64, error) {
+	if len(b) != 8 {
+		return 0, fmt.Errorf("snowflake: kafka key must be 8 bytes, got %d", len(b))
+	}
+	return int64(binary.BigEndian.Uint64(b)), nil
+}
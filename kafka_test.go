@@ -0,0 +1,27 @@
+package snowflake
+
+import "testing"
+
+func TestNextKafkaKey(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	key, err := s.NextKafkaKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := KafkaKeyToID(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != s.LastID() {
+		t.Errorf("id = %d, want %d", id, s.LastID())
+	}
+
+	if _, err = KafkaKeyToID(key[:7]); err == nil {
+		t.Error("expected error for short key")
+	}
+}
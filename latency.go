@@ -0,0 +1,93 @@
+package snowflake
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// latencyHistogram NextID 耗时的直方图，各个桶的计数使用原子操作，记录时不需要额外加锁
+type latencyHistogram struct {
+	// 各个桶的上界，升序
+	bounds []time.Duration
+	// 各个桶的计数，最后一个桶记录超过所有上界的耗时
+	counts []uint64
+}
+
+// record 记录一次耗时
+func (h *latencyHistogram) record(d time.Duration) {
+	i := sort.Search(len(h.bounds), func(i int) bool {
+		return h.bounds[i] >= d
+	})
+	atomic.AddUint64(&h.counts[i], 1)
+}
+
+// EnableLatencyTracking 开始统计 NextID 的耗时，histogramBuckets 为直方图各个桶的上界
+// 不需要 Prometheus 等监控系统，也可以通过 LatencyPercentile 确认 p99 等耗时是否可以接受
+// 重复调用会使用新的桶重新开始统计
+func (s *Snowflake) EnableLatencyTracking(histogramBuckets []time.Duration) {
+	bounds := append([]time.Duration(nil), histogramBuckets...)
+	sort.Slice(bounds, func(i, j int) bool {
+		return bounds[i] < bounds[j]
+	})
+
+	s.latency.Store(&latencyHistogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	})
+}
+
+// ResetLatencyHistogram 清空统计的耗时，桶不变
+func (s *Snowflake) ResetLatencyHistogram() {
+	if h := s.latencyHistogram(); h != nil {
+		s.EnableLatencyTracking(h.bounds)
+	}
+}
+
+// LatencyPercentile 返回 NextID 耗时的第 p 百分位数（p 为 0 到 100），在桶内线性插值得到
+// 没有开启统计或者还没有数据时返回 0，落在最后一个桶之外时返回最大的上界
+func (s *Snowflake) LatencyPercentile(p float64) time.Duration {
+	h := s.latencyHistogram()
+	if h == nil {
+		return 0
+	}
+
+	counts := make([]uint64, len(h.counts))
+	var total uint64
+	for i := range h.counts {
+		counts[i] = atomic.LoadUint64(&h.counts[i])
+		total += counts[i]
+	}
+	if total == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(total)
+
+	var cum float64
+	for i, c := range counts[:len(h.bounds)] {
+		if c == 0 {
+			continue
+		}
+		if cum+float64(c) >= rank {
+			var lo time.Duration
+			if i > 0 {
+				lo = h.bounds[i-1]
+			}
+			frac := (rank - cum) / float64(c)
+			return lo + time.Duration(frac*float64(h.bounds[i]-lo))
+		}
+		cum += float64(c)
+	}
+
+	if len(h.bounds) == 0 {
+		return 0
+	}
+	return h.bounds[len(h.bounds)-1]
+}
+
+// latencyHistogram 返回当前的直方图，没有开启统计时返回 nil
+func (s *Snowflake) latencyHistogram() *latencyHistogram {
+	h, _ := s.latency.Load().(*latencyHistogram)
+	return h
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyPercentile(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	if s.LatencyPercentile(99) != 0 {
+		t.Error("expected 0 before tracking is enabled")
+	}
+
+	buckets := []time.Duration{time.Millisecond, time.Microsecond, 10 * time.Millisecond}
+	s.EnableLatencyTracking(buckets)
+
+	// 直接记录耗时，验证插值
+	h := s.latencyHistogram()
+	for i := 0; i < 100; i++ {
+		h.record(500 * time.Nanosecond)
+	}
+	if p := s.LatencyPercentile(50); p != 500*time.Nanosecond {
+		t.Errorf("p50 = %s, want 500ns", p)
+	}
+
+	s.ResetLatencyHistogram()
+	for i := 0; i < 100; i++ {
+		next(t, s)
+	}
+	if p := s.LatencyPercentile(99); p <= 0 || p > 10*time.Millisecond {
+		t.Errorf("p99 = %s", p)
+	}
+}
@@ -0,0 +1,180 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// BitLayout id 的结构，即各部分的 bit 长度以及排列方式
+type BitLayout struct {
+	// 时间部分 bit 长度
+	Time int64
+	// workerID 部分 bit 长度
+	WorkerID int64
+	// 序列号部分 bit 长度
+	Sequence int64
+	// 非自增，如果设置了，则 workerID 和序列号的位置互换
+	NonIncrement bool
+}
+
+// DefaultBitLayout 默认的 id 结构
+var DefaultBitLayout = BitLayout{
+	Time:     bitLenTime,
+	WorkerID: bitLenWorkerID,
+	Sequence: bitLenSequence,
+}
+
+// Layout 返回当前配置的 id 结构
+func (s *Snowflake) Layout() BitLayout {
+	return BitLayout{
+		Time:         s.bitLenTime,
+		WorkerID:     s.bitLenWorkerID,
+		Sequence:     s.bitLenSequence,
+		NonIncrement: s.nonIncrement,
+	}
+}
+
+// Compose 把时间、workerID、序列号三个部分组合成 id
+// 结构为：
+//
+//	time--work--sequence
+//
+// 如果设置了 NonIncrement，则为
+//
+//	time--sequence--work
+func (l BitLayout) Compose(t, workerID, sequenceID int64) int64 {
+	if !l.NonIncrement {
+		return t<<(l.WorkerID+l.Sequence) | workerID<<l.Sequence | sequenceID
+	}
+	return t<<(l.WorkerID+l.Sequence) | sequenceID<<l.WorkerID | workerID
+}
+
+// Decompose 把 id 拆分为时间、workerID、序列号三个部分，是 Compose 的逆运算
+func (l BitLayout) Decompose(id int64) (t, workerID, sequenceID int64) {
+	u := uint64(id)
+	t = int64(u >> (l.WorkerID + l.Sequence))
+
+	low := int64(u & (1<<(l.WorkerID+l.Sequence) - 1))
+	if !l.NonIncrement {
+		workerID = low >> l.Sequence
+		sequenceID = low & (1<<l.Sequence - 1)
+	} else {
+		sequenceID = low >> l.WorkerID
+		workerID = low & (1<<l.WorkerID - 1)
+	}
+
+	return
+}
+
+// compose 按照当前配置的结构组合 id
+func (s *Snowflake) compose(t, workerID, sequenceID int64) int64 {
+	return s.Layout().Compose(t, workerID, sequenceID)
+}
+
+// decompose 按照当前配置的结构拆分 id
+func (s *Snowflake) decompose(id int64) (t, workerID, sequenceID int64) {
+	return s.Layout().Decompose(id)
+}
+
+// timeField 把绝对时间转换为 id 的时间部分
+func (s *Snowflake) timeField(t time.Time) (int64, error) {
+	field := t.UnixMilli() - s.epoch
+	if field < 0 {
+		return 0, fmt.Errorf("snowflake: time %s is before epoch", t.Format(time.RFC3339))
+	}
+	if field >= 1<<s.bitLenTime {
+		return 0, fmt.Errorf("snowflake: time %s overflows %d time bits", t.Format(time.RFC3339), s.bitLenTime)
+	}
+	return field, nil
+}
+
+// TimeFromID 取出 id 的生成时间，设置了倒序时会还原被取反的时间部分
+func (s *Snowflake) TimeFromID(id int64) time.Time {
+	return time.UnixMilli(s.DecodeTimestamp(id))
+}
+
+// DecodeTimestamp 取出 id 的生成时间，为 Unix 毫秒时间戳（不是距离 epoch 的毫秒数）
+// 和 TimeFromID 相同，但不需要构造 time.Time，没有内存分配，适合直接用整数时间戳查询数据库等场景
+func (s *Snowflake) DecodeTimestamp(id int64) int64 {
+	t := int64(uint64(id) >> (s.bitLenWorkerID + s.bitLenSequence))
+	if s.descending {
+		t = 1<<s.bitLenTime - 1 - t
+	}
+	return t + s.epoch
+}
+
+// WorkerFromID 取出 id 的 workerID 部分
+func (s *Snowflake) WorkerFromID(id int64) int64 {
+	_, w, _ := s.decompose(id)
+	return w
+}
+
+// IDIsFromThisWorker 判断 id 是否由本实例的 workerID 生成，用于校验客户端传回的 id，id 为 0 时返回 false
+// 注意这只比较 workerID 部分，不能防止伪造，需要更严格的校验可以使用 ValidateID
+func (s *Snowflake) IDIsFromThisWorker(id int64) bool {
+	return id != 0 && s.WorkerFromID(id) == s.workerID
+}
+
+// IDRange 计算某个 worker 在 [start, end] 时间范围内生成的 id 的范围
+// 最小值的序列号为 0，最大值的序列号为最大值，可用于数据库中按 worker 扫描 id
+func (s *Snowflake) IDRange(workerID int64, start, end time.Time) (minID, maxID int64, err error) {
+	if workerID < 0 || workerID >= 1<<s.bitLenWorkerID {
+		return 0, 0, fmt.Errorf("snowflake: worker id %d out of range [0, %d)", workerID, int64(1)<<s.bitLenWorkerID)
+	}
+	if end.Before(start) {
+		return 0, 0, errors.New("snowflake: end is before start")
+	}
+
+	st, err := s.timeField(start)
+	if err != nil {
+		return 0, 0, err
+	}
+	et, err := s.timeField(end)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return s.compose(st, workerID, 0), s.compose(et, workerID, s.sequenceMask), nil
+}
+
+// TagFromID 取出 WithTypeTag 设置的类型标签，即序列号的高 tagBits 位
+func TagFromID(id int64, layout BitLayout, tagBits int64) int64 {
+	_, _, seq := layout.Decompose(id)
+	return seq >> (layout.Sequence - tagBits)
+}
+
+// MigrateWorkerID 把一批 id 的 workerID 部分从 oldWorkerID 改为 newWorkerID，用于 worker 重新分配后的历史数据迁移
+// 任何一个 id 的 workerID 不等于 oldWorkerID 时返回错误，不修改 ids
+func MigrateWorkerID(ids []int64, oldWorkerID, newWorkerID int64, layout BitLayout) ([]int64, error) {
+	if newWorkerID < 0 || newWorkerID >= 1<<layout.WorkerID {
+		return nil, fmt.Errorf("snowflake: worker id %d out of range [0, %d)", newWorkerID, int64(1)<<layout.WorkerID)
+	}
+
+	migrated := make([]int64, len(ids))
+	for i, id := range ids {
+		t, w, seq := layout.Decompose(id)
+		if w != oldWorkerID {
+			return nil, fmt.Errorf("snowflake: id %d has worker id %d, want %d", id, w, oldWorkerID)
+		}
+		migrated[i] = layout.Compose(t, newWorkerID, seq)
+	}
+
+	return migrated, nil
+}
+
+// ContiguousIDRange 计算从绝对毫秒时间 start 开始连续调用 count 次 NextID 得到的 id 范围，用于批量插入前预留 id
+// 假设只有一个 worker（workerID 为 0），序列号从 0 开始，所有 id 都在 start 这一毫秒内
+// count 超过每毫秒的序列号数量时，NextID 会等待下一毫秒，id 不再连续，返回错误
+func ContiguousIDRange(start, count int64, layout BitLayout, epoch int64) (minID, maxID int64, err error) {
+	if count <= 0 || count > 1<<layout.Sequence {
+		return 0, 0, fmt.Errorf("snowflake: count %d out of range [1, %d]", count, int64(1)<<layout.Sequence)
+	}
+
+	t := start - epoch
+	if t < 0 || t >= 1<<layout.Time {
+		return 0, 0, fmt.Errorf("snowflake: time %d out of range", start)
+	}
+
+	return layout.Compose(t, 0, 0), layout.Compose(t, 0, count-1), nil
+}
@@ -0,0 +1,214 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIDRange(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	start := time.Now()
+	id := next(t, s)
+	end := time.Now()
+
+	minID, maxID, err := s.IDRange(s.WorkerID(), start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id < minID || id > maxID {
+		t.Errorf("id %d not in [%d, %d]", id, minID, maxID)
+	}
+
+	if _, _, err = s.IDRange(1<<s.BitLenWorkerID(), start, end); err == nil {
+		t.Error("expected error for worker id out of range")
+	}
+	if _, _, err = s.IDRange(0, end.Add(time.Second), start); err == nil {
+		t.Error("expected error for end before start")
+	}
+}
+
+func TestMigrateWorkerID(t *testing.T) {
+	for _, layout := range []BitLayout{DefaultBitLayout, {Time: 41, WorkerID: 10, Sequence: 12, NonIncrement: true}} {
+		ids := []int64{layout.Compose(100, 3, 0), layout.Compose(200, 3, 7)}
+
+		migrated, err := MigrateWorkerID(ids, 3, 9, layout)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i, id := range migrated {
+			ot, _, oseq := layout.Decompose(ids[i])
+			nt, nw, nseq := layout.Decompose(id)
+			if nt != ot || nseq != oseq || nw != 9 {
+				t.Errorf("migrated id %d = (%d, %d, %d)", id, nt, nw, nseq)
+			}
+		}
+
+		if _, err = MigrateWorkerID(ids, 4, 9, layout); err == nil {
+			t.Error("expected error for mismatched worker id")
+		}
+		if _, err = MigrateWorkerID(ids, 3, 1<<layout.WorkerID, layout); err == nil {
+			t.Error("expected error for out of range worker id")
+		}
+	}
+}
+
+func TestTimeFromID(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}, {WithEpoch(1288834974657)}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		before := time.Now().UnixMilli()
+		got := s.TimeFromID(next(t, s)).UnixMilli()
+		if got < before || got > time.Now().UnixMilli() {
+			t.Errorf("time %d out of range", got)
+		}
+	}
+}
+
+func TestIDIsFromThisWorker(t *testing.T) {
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 7, nil }))
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	if s.WorkerFromID(id) != 7 {
+		t.Errorf("worker = %d, want 7", s.WorkerFromID(id))
+	}
+	if !s.IDIsFromThisWorker(id) {
+		t.Error("id should be from this worker")
+	}
+
+	tm, _, seq := s.decompose(id)
+	if s.IDIsFromThisWorker(s.compose(tm, 8, seq)) {
+		t.Error("id from another worker should not match")
+	}
+	if s.IDIsFromThisWorker(0) {
+		t.Error("zero id should not match")
+	}
+}
+
+func TestParseAllLayouts(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour).UnixMilli()
+
+	var layouts []BitLayout
+	for _, l := range []BitLayout{
+		{Time: 41, WorkerID: 12, Sequence: 10},
+		{Time: 41, WorkerID: 10, Sequence: 12},
+		{Time: 39, WorkerID: 16, Sequence: 8},
+		{Time: 42, WorkerID: 5, Sequence: 16},
+		{Time: 45, WorkerID: 8, Sequence: 10},
+		{Time: 35, WorkerID: 20, Sequence: 8},
+		{Time: 40, WorkerID: 1, Sequence: 22},
+		{Time: 50, WorkerID: 12, Sequence: 1},
+	} {
+		layouts = append(layouts, l)
+		l.NonIncrement = true
+		layouts = append(layouts, l)
+	}
+
+	for _, l := range layouts {
+		opts := []Option{
+			WithEpoch(epoch),
+			WithLen(l.Time, l.WorkerID, l.Sequence),
+			WithWorkID(func() (int64, error) { return 1, nil }),
+		}
+		if l.NonIncrement {
+			opts = append(opts, WithNonIncrement())
+		}
+
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			t.Fatalf("%+v: %v", l, err)
+		}
+
+		for i := 0; i < 10; i++ {
+			id, tm, w, seq, err := s.NextIDWithFields()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			gt, gw, gseq := l.Decompose(id)
+			if gt != tm || gw != w || gseq != seq {
+				t.Fatalf("%+v: id %d parsed as (%d, %d, %d), want (%d, %d, %d)", l, id, gt, gw, gseq, tm, w, seq)
+			}
+			if l.Compose(gt, gw, gseq) != id {
+				t.Fatalf("%+v: id %d does not round trip", l, id)
+			}
+
+			pt, pw, pseq := s.Parse(uint64(id))
+			if pt != uint64(tm+epoch) || pw != uint64(w) || pseq != uint64(seq) {
+				t.Fatalf("%+v: Parse(%d) = (%d, %d, %d), want (%d, %d, %d)", l, id, pt, pw, pseq, tm+epoch, w, seq)
+			}
+		}
+	}
+}
+
+func TestDecodeTimestamp(t *testing.T) {
+	s, err := NewSnowflake(WithEpoch(1288834974657))
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	if got, want := s.DecodeTimestamp(id), s.TimeFromID(id).UnixMilli(); got != want {
+		t.Errorf("timestamp = %d, want %d", got, want)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { s.DecodeTimestamp(id) }); allocs != 0 {
+		t.Errorf("DecodeTimestamp allocates %v times", allocs)
+	}
+}
+
+func BenchmarkDecodeTimestamp(b *testing.B) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+	id := next(b, s)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s.DecodeTimestamp(id)
+	}
+}
+
+func TestContiguousIDRange(t *testing.T) {
+	now := time.Now().UnixMilli()
+	s, err := NewSnowflake(
+		WithClock(func() int64 { return now }),
+		WithWorkID(func() (int64, error) { return 0, nil }),
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	minID, maxID, err := ContiguousIDRange(now, 100, s.Layout(), s.Epoch())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := next(t, s)
+	for i := 1; i < 100; i++ {
+		next(t, s)
+	}
+	if first != minID || s.LastID() != maxID {
+		t.Errorf("range [%d, %d], want [%d, %d]", minID, maxID, first, s.LastID())
+	}
+	if maxID-minID != 99 {
+		t.Errorf("range should be contiguous, got %d ids", maxID-minID+1)
+	}
+
+	if _, _, err = ContiguousIDRange(now, s.SequenceMask()+2, s.Layout(), s.Epoch()); err == nil {
+		t.Error("expected error for count over one millisecond")
+	}
+	if _, _, err = ContiguousIDRange(-1, 1, s.Layout(), s.Epoch()); err == nil {
+		t.Error("expected error for time before epoch")
+	}
+}
@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"sync"
+)
+
+// LazySnowflake 延迟初始化的雪花算法，第一次调用 NextID 时才调用 NewSnowflake
+// 适用于在 main 初始化阶段创建，但此时网络还没准备好，无法通过 IP 得到 workerID 的场景
+type LazySnowflake struct {
+	// 锁
+	mutex sync.Mutex
+
+	// 创建时的配置
+	opts []Option
+	// 初始化成功后的实例
+	sf *Snowflake
+}
+
+// NewLazy 新建一个延迟初始化的雪花算法，只保存配置，不会调用 NewSnowflake
+func NewLazy(opts ...Option) *LazySnowflake {
+	return &LazySnowflake{opts: opts}
+}
+
+// NextID 生成下一个 id，第一次调用时初始化
+// 初始化失败时返回错误，下一次调用会重新尝试初始化，成功之后不会再初始化
+func (l *LazySnowflake) NextID() (int64, error) {
+	sf, err := l.get()
+	if err != nil {
+		return 0, err
+	}
+	return sf.NextID()
+}
+
+// Initialized 是否已经初始化成功
+func (l *LazySnowflake) Initialized() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	return l.sf != nil
+}
+
+// get 获取初始化后的实例，还没有初始化则进行初始化
+func (l *LazySnowflake) get() (*Snowflake, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.sf != nil {
+		return l.sf, nil
+	}
+
+	sf, err := NewSnowflake(l.opts...)
+	if err != nil {
+		return nil, err
+	}
+	l.sf = sf
+
+	return sf, nil
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLazySnowflake(t *testing.T) {
+	calls := 0
+	ready := false
+
+	l := NewLazy(WithWorkID(func() (int64, error) {
+		calls++
+		if !ready {
+			return 0, errors.New("network not ready")
+		}
+		return 1, nil
+	}))
+
+	if l.Initialized() || calls != 0 {
+		t.Fatal("NewLazy should not initialize")
+	}
+
+	if _, err := l.NextID(); err == nil {
+		t.Fatal("expected initialization error")
+	}
+
+	ready = true
+	for i := 0; i < 3; i++ {
+		if _, err := l.NextID(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !l.Initialized() || calls != 2 {
+		t.Errorf("initialized = %v, calls = %d", l.Initialized(), calls)
+	}
+}
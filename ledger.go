@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Ledger 只追加的 id 账本，可用于构建内存中的事件存储
+// 利用 id 的单调递增，按 id 查找位置为 O(log n)，按位置查找 id 为 O(1)
+type Ledger struct {
+	// 锁，查找时加读锁，追加时加写锁
+	mutex sync.RWMutex
+
+	// 按追加顺序保存的 id
+	ids []int64
+}
+
+// Append 使用 sf 生成一个 id 并追加到账本，返回 id 和它从 0 开始的位置
+// 如果 id 不大于上一个 id（比如 sf 设置了非自增），会返回错误，保证账本有序
+func (l *Ledger) Append(sf *Snowflake) (int64, int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	id, err := sf.NextID()
+	if err != nil {
+		return 0, 0, err
+	}
+	if n := len(l.ids); n > 0 && id <= l.ids[n-1] {
+		return 0, 0, fmt.Errorf("snowflake: id %d is not greater than the last ledger entry %d", id, l.ids[n-1])
+	}
+
+	l.ids = append(l.ids, id)
+
+	return id, len(l.ids) - 1, nil
+}
+
+// PositionOfID 查找 id 在账本中的位置
+func (l *Ledger) PositionOfID(id int64) (int, bool) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	i := sort.Search(len(l.ids), func(i int) bool {
+		return l.ids[i] >= id
+	})
+	if i < len(l.ids) && l.ids[i] == id {
+		return i, true
+	}
+	return 0, false
+}
+
+// IDAtPosition 返回账本中 pos 位置的 id
+func (l *Ledger) IDAtPosition(pos int) (int64, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	if pos < 0 || pos >= len(l.ids) {
+		return 0, fmt.Errorf("snowflake: ledger position %d out of range [0, %d)", pos, len(l.ids))
+	}
+	return l.ids[pos], nil
+}
+
+// Len 返回账本中 id 的个数
+func (l *Ledger) Len() int {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	return len(l.ids)
+}
@@ -0,0 +1,36 @@
+package snowflake
+
+import "testing"
+
+func TestLedger(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	var l Ledger
+	for i := 0; i < 100; i++ {
+		id, pos, err := l.Append(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pos != i {
+			t.Fatalf("pos = %d, want %d", pos, i)
+		}
+
+		got, ok := l.PositionOfID(id)
+		if !ok || got != pos {
+			t.Fatalf("PositionOfID(%d) = %d, %v", id, got, ok)
+		}
+		if at, err := l.IDAtPosition(pos); err != nil || at != id {
+			t.Fatalf("IDAtPosition(%d) = %d, %v", pos, at, err)
+		}
+	}
+
+	if _, ok := l.PositionOfID(1); ok {
+		t.Error("unknown id should not be found")
+	}
+	if _, err = l.IDAtPosition(l.Len()); err == nil {
+		t.Error("expected error for position out of range")
+	}
+}
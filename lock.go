@@ -0,0 +1,38 @@
+package snowflake
+
+import (
+	"hash/fnv"
+)
+
+// NextLockID 生成一个用于分布式锁的 id，把 fnv32(resource) 异或到 workerID 部分
+// 持有者之后可以通过 LockIDMatchesResource 校验 id 是否属于这个资源，而不需要额外的存储
+//
+// 注意这只是一个轻量的归属标记，不是安全机制：
+// 1. 任何知道 workerID 和资源名的人都可以伪造
+// 2. 只有 workerID 相同的实例才能校验
+// 3. workerID 部分被改写，可能和其它 worker 生成的普通 id 重复，不要和普通 id 混用
+func (s *Snowflake) NextLockID(resource string) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return 0, err
+	}
+
+	t, w, seq := s.decompose(id)
+	return s.compose(t, w^s.resourceHash(resource), seq), nil
+}
+
+// LockIDMatchesResource 校验 id 是否是本实例为 resource 生成的锁 id
+func (s *Snowflake) LockIDMatchesResource(id int64, resource string) bool {
+	_, w, _ := s.decompose(id)
+	return w^s.resourceHash(resource) == s.workerID
+}
+
+// resourceHash 计算资源名的 fnv32，截取为 workerID 部分的长度
+func (s *Snowflake) resourceHash(resource string) int64 {
+	h := fnv.New32()
+	_, _ = h.Write([]byte(resource))
+	return int64(h.Sum32()) & (1<<s.bitLenWorkerID - 1)
+}
@@ -0,0 +1,21 @@
+package snowflake
+
+import "testing"
+
+func TestNextLockID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	id, err := s.NextLockID("order:42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.LockIDMatchesResource(id, "order:42") {
+		t.Error("lock id should match its resource")
+	}
+	if s.LockIDMatchesResource(id, "order:43") {
+		t.Error("lock id should not match another resource")
+	}
+}
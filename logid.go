@@ -0,0 +1,29 @@
+package snowflake
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+)
+
+// idTimeLayout 日志中 id 时间的格式，为精确到毫秒的 RFC 3339
+const idTimeLayout = "2006-01-02T15:04:05.000Z07:00"
+
+// LogID 打印一条带 id 各部分的结构化日志，避免每次打印 id 都要先拆分再格式化
+// 属性为 id（十进制字符串，避免 JSON 中丢失精度）、id_time（RFC 3339）、id_worker、id_seq，之后是 extra
+// logger 为 nil 时使用 slog.Default()
+func (s *Snowflake) LogID(logger *slog.Logger, level slog.Level, id int64, msg string, extra ...slog.Attr) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	_, w, seq := s.decompose(id)
+	attrs := append([]slog.Attr{
+		slog.String("id", strconv.FormatInt(id, 10)),
+		slog.String("id_time", s.TimeFromID(id).UTC().Format(idTimeLayout)),
+		slog.Int64("id_worker", w),
+		slog.Int64("id_seq", seq),
+	}, extra...)
+
+	logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
@@ -0,0 +1,50 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strconv"
+	"testing"
+)
+
+func TestLogID(t *testing.T) {
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 3, nil }))
+	if err != nil {
+		panic(err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	id := next(t, s)
+	s.LogID(logger, slog.LevelInfo, id, "created", slog.String("user", "alice"))
+
+	var rec map[string]interface{}
+	if err = json.Unmarshal(buf.Bytes(), &rec); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"msg":       "created",
+		"id":        strconv.FormatInt(id, 10),
+		"id_time":   s.TimeFromID(id).UTC().Format(idTimeLayout),
+		"id_worker": float64(3),
+		"user":      "alice",
+	}
+	for k, v := range want {
+		if rec[k] != v {
+			t.Errorf("%s = %v, want %v", k, rec[k], v)
+		}
+	}
+	if _, ok := rec["id_seq"]; !ok {
+		t.Error("missing id_seq")
+	}
+
+	// 低于 handler 级别的日志不打印
+	buf.Reset()
+	s.LogID(logger, slog.LevelDebug, id, "ignored")
+	if buf.Len() != 0 {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
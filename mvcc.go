@@ -0,0 +1,31 @@
+package snowflake
+
+// SnapshotID 返回当前时间对应的读时间戳，即 workerID 和序列号都为 0 的 id
+// 表示“这一毫秒或者更早生成的任意 id”，可以作为按时间点查询的下界
+// 不会推进序列号，也不会更新 lastTime，不影响后续生成的 id
+// 设置了倒序时时间部分和 NextID 一样取反，此时更早的 id 反而更大，比较的方向也要反过来
+func (s *Snowflake) SnapshotID() int64 {
+	t := s.clock() - s.epoch
+	if s.descending {
+		t = 1<<s.bitLenTime - 1 - t
+	}
+	return t << (s.bitLenWorkerID + s.bitLenSequence)
+}
+
+// NextMVCCVersion 生成一个写 id，同时返回生成之前的 lastTime（距离 epoch 的毫秒数）作为读时间戳
+// 两者在同一次加锁中得到，读时间戳之前（时间部分不大于 readTs）的写入都在这个事务开始前完成，
+// 读取时使用这个时间戳对应的快照即可，还没有生成过 id 时 readTs 为 0
+func (s *Snowflake) NextMVCCVersion() (writeID, readTs int64, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.lastID != 0 {
+		readTs = s.lastTime - s.epoch
+	}
+
+	if writeID, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+
+	return writeID, readTs, nil
+}
@@ -0,0 +1,63 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotID(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		testSnapshotID(t, opts...)
+	}
+}
+
+func testSnapshotID(t *testing.T, opts ...Option) {
+	s, err := NewSnowflake(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	seq := s.SequenceID()
+
+	snap := s.SnapshotID()
+	if s.DecodeTimestamp(snap) < s.DecodeTimestamp(id) {
+		t.Errorf("snapshot %d is before id %d", snap, id)
+	}
+	if d := time.Since(s.TimeFromID(snap)); d < 0 || d > time.Second {
+		t.Errorf("snapshot time %s is not now", s.TimeFromID(snap))
+	}
+	if snap&(1<<(s.BitLenWorkerID()+s.BitLenSequence())-1) != 0 {
+		t.Errorf("snapshot %d has non-zero worker or sequence", snap)
+	}
+	if s.SequenceID() != seq {
+		t.Error("snapshot should not advance the sequence")
+	}
+}
+
+func TestNextMVCCVersion(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	_, readTs, err := s.NextMVCCVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readTs != 0 {
+		t.Errorf("first readTs = %d, want 0", readTs)
+	}
+
+	prev := s.Time()
+	writeID, readTs, err := s.NextMVCCVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readTs != prev {
+		t.Errorf("readTs = %d, want %d", readTs, prev)
+	}
+	if wt, _, _ := s.decompose(writeID); wt < readTs {
+		t.Errorf("write time %d before read timestamp %d", wt, readTs)
+	}
+}
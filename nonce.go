@@ -0,0 +1,29 @@
+package snowflake
+
+import "encoding/binary"
+
+// NextNonce 生成一个 12 字节的 AES-GCM nonce：
+//
+//	id(8)--序列号左移填满 32 位(4)
+//
+// nonce 的唯一性来自 id 的唯一性
+// 注意这不是密码学意义上的随机 nonce，只是一个结构化的 nonce，可以被猜到，不能当作密钥使用
+func (s *Snowflake) NextNonce() (b [12]byte, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return b, err
+	}
+
+	seq := uint32(s.sequenceField())
+	if s.bitLenSequence < 32 {
+		seq <<= 32 - s.bitLenSequence
+	}
+
+	binary.BigEndian.PutUint64(b[:8], uint64(id))
+	binary.BigEndian.PutUint32(b[8:], seq)
+
+	return b, nil
+}
@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestNextNonce(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[[12]byte]bool)
+	for i := 0; i < 1000; i++ {
+		b, err := s.NextNonce()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[b] {
+			t.Fatalf("duplicate nonce %x", b)
+		}
+		seen[b] = true
+
+		if id := int64(binary.BigEndian.Uint64(b[:8])); id != s.LastID() {
+			t.Fatalf("id = %d, want %d", id, s.LastID())
+		}
+		_, _, seq := s.decompose(s.LastID())
+		if got := int64(binary.BigEndian.Uint32(b[8:]) >> (32 - s.bitLenSequence)); got != seq {
+			t.Fatalf("sequence = %d, want %d", got, seq)
+		}
+	}
+}
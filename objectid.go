@@ -0,0 +1,58 @@
+package snowflake
+
+import "fmt"
+
+// NextObjectID 生成一个 id，并编码为 MongoDB ObjectID 兼容的 12 字节结构：
+//
+//	Unix 秒(4)--workerID(3)--秒内毫秒(10 bit)+序列号(30 bit)(5)
+//
+// 方便从 MongoDB 迁移到其它数据库时保持 id 格式不变
+// 序列号每毫秒都会重置，所以要带上秒内的毫秒数，否则同一秒内不同毫秒的 id 会重复；
+// 因此不再保存进程 id，workerID 已经能区分不同的进程
+// workerID 最多 24 位，序列号最多 30 位
+func (s *Snowflake) NextObjectID() (b [12]byte, err error) {
+	if s.bitLenWorkerID > 24 || s.bitLenSequence > 30 {
+		return b, fmt.Errorf("snowflake: worker (%d bits) or sequence (%d bits) do not fit in object id", s.bitLenWorkerID, s.bitLenSequence)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err = s.nextID(); err != nil {
+		return b, err
+	}
+
+	ms := s.time + s.epoch
+	sec := ms / 1000
+	low := (ms%1000)<<30 | s.sequenceField()
+
+	b[0], b[1], b[2], b[3] = byte(sec>>24), byte(sec>>16), byte(sec>>8), byte(sec)
+	b[4], b[5], b[6] = byte(s.workerID>>16), byte(s.workerID>>8), byte(s.workerID)
+	b[7], b[8], b[9], b[10], b[11] = byte(low>>32), byte(low>>24), byte(low>>16), byte(low>>8), byte(low)
+
+	return b, nil
+}
+
+// ParseObjectID 把 NextObjectID 生成的 ObjectID 转换回 id
+func ParseObjectID(b [12]byte, layout BitLayout, epoch int64) (int64, error) {
+	sec := int64(b[0])<<24 | int64(b[1])<<16 | int64(b[2])<<8 | int64(b[3])
+	workerID := int64(b[4])<<16 | int64(b[5])<<8 | int64(b[6])
+	low := int64(b[7])<<32 | int64(b[8])<<24 | int64(b[9])<<16 | int64(b[10])<<8 | int64(b[11])
+	ms, seq := low>>30, low&(1<<30-1)
+	if ms >= 1000 {
+		return 0, fmt.Errorf("snowflake: object id millisecond %d out of range", ms)
+	}
+
+	t := sec*1000 + ms - epoch
+	if t < 0 || t >= 1<<layout.Time {
+		return 0, fmt.Errorf("snowflake: object id time %d out of range", sec)
+	}
+	if workerID >= 1<<layout.WorkerID {
+		return 0, fmt.Errorf("snowflake: object id worker %d out of range", workerID)
+	}
+	if seq >= 1<<layout.Sequence {
+		return 0, fmt.Errorf("snowflake: object id sequence %d out of range", seq)
+	}
+
+	return layout.Compose(t, workerID, seq), nil
+}
@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextObjectID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	b, err := s.NextObjectID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := ParseObjectID(b, s.Layout(), s.Epoch())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := s.compose(s.Time(), s.WorkerID(), s.SequenceID())
+	if id != want {
+		t.Errorf("got %d, want %d", id, want)
+	}
+}
+
+func TestNextObjectIDUnique(t *testing.T) {
+	// 时钟从一秒的开始每次调用前进 1ms，每个 id 都在新的毫秒，序列号都是 0
+	now := time.Now().UnixMilli() / 1000 * 1000
+	s, err := NewSnowflake(WithClock(func() int64 {
+		now++
+		return now - 1
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[[12]byte]bool)
+	for i := 0; i < 100; i++ {
+		b, err := s.NextObjectID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[b] {
+			t.Fatalf("duplicate object id %x", b)
+		}
+		seen[b] = true
+	}
+}
@@ -0,0 +1,79 @@
+package snowflake
+
+import (
+	"time"
+)
+
+// PartitionKey 根据 id 的时间部分计算分区键，用于 S3 前缀、DynamoDB 按天分区等场景
+// 先取出时间部分并加上 epoch 得到绝对毫秒时间，再按 partitionSizeSecs 秒划分分区，
+// 返回分区的起始时间，格式为 "2024-01-15T10:00:00Z"
+// lenTime 为时间部分的 bit 长度，workerID 和序列号一共占 63-lenTime 位
+func PartitionKey(id int64, partitionSizeSecs, epoch, lenTime int64) string {
+	size := partitionSizeSecs * 1000
+	ms := int64(uint64(id)>>(63-lenTime)) + epoch
+
+	return time.UnixMilli(ms / size * size).UTC().Format("2006-01-02T15:04:05Z")
+}
+
+// BucketRange 计算第 bucket 个分区（从 Unix 时间 0 开始，每个分区 partitionSizeSecs 秒）对应的 id 范围
+// 结果包含所有 worker 和序列号，超出时间部分表示范围的会被截断
+func BucketRange(bucket, partitionSizeSecs, epoch, lenTime int64) (minID, maxID int64) {
+	return bucketRange(bucket, partitionSizeSecs*1000, epoch, lenTime, false)
+}
+
+// IDToTimeBucket 计算 id 所在的时间桶，即 floor(绝对毫秒时间 / bucketSizeMs)，桶号随时间单调递增
+// 用于 TimescaleDB、InfluxDB 等时序数据库的分块路由
+func (s *Snowflake) IDToTimeBucket(id int64, bucketSizeMs int64) int64 {
+	return s.DecodeTimestamp(id) / bucketSizeMs
+}
+
+// BucketToIDRange 计算时间桶对应的 id 范围，是 IDToTimeBucket 的逆运算
+// 设置了倒序时时间部分是取反的，范围的两端也会对应地反过来
+func (s *Snowflake) BucketToIDRange(bucket, bucketSizeMs int64) (minID, maxID int64) {
+	return bucketRange(bucket, bucketSizeMs, s.epoch, s.bitLenTime, s.descending)
+}
+
+// TimeBucket 计算 id 所在的时间桶，和 IDToTimeBucket 相同，用于按时间分区的数据库
+func (s *Snowflake) TimeBucket(id int64, bucketSizeMs int64) int64 {
+	return s.IDToTimeBucket(id, bucketSizeMs)
+}
+
+// BucketRange 计算时间桶对应的 id 范围，包含所有 worker 和序列号，和 BucketToIDRange 相同
+// 对于任意合法的 id，TimeBucket(id, bucketSizeMs) == bucket 当且仅当 id 在 [minID, maxID] 中
+func (s *Snowflake) BucketRange(bucket, bucketSizeMs int64) (minID, maxID int64) {
+	return s.BucketToIDRange(bucket, bucketSizeMs)
+}
+
+// AggregationKey 计算两级聚合（先按 worker，再按时间桶）的路由键，用于 Flink、Spark Streaming 等流处理
+// timeKey 为 floor(绝对毫秒时间 / bucketMs)，workerKey 为 workerID % numWorkers
+// bucketMs 或 numWorkers 小于等于 0 时都返回 -1
+func (s *Snowflake) AggregationKey(id int64, bucketMs, numWorkers int64) (timeKey, workerKey int64) {
+	if bucketMs <= 0 || numWorkers <= 0 {
+		return -1, -1
+	}
+	return s.DecodeTimestamp(id) / bucketMs, s.WorkerFromID(id) % numWorkers
+}
+
+// bucketRange 计算第 bucket 个大小为 sizeMs 毫秒的时间桶对应的 id 范围，descending 表示时间部分是取反的
+func bucketRange(bucket, sizeMs, epoch, lenTime int64, descending bool) (minID, maxID int64) {
+	shift := 63 - lenTime
+	maxTime := int64(1)<<lenTime - 1
+
+	start := bucket*sizeMs - epoch
+	end := (bucket+1)*sizeMs - epoch - 1
+
+	if start < 0 {
+		start = 0
+	}
+	if end > maxTime {
+		end = maxTime
+	}
+	if end < start {
+		return 0, -1
+	}
+	if descending {
+		start, end = maxTime-end, maxTime-start
+	}
+
+	return start << shift, end<<shift | (int64(1)<<shift - 1)
+}
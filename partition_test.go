@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPartitionKey(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	key := PartitionKey(id, 3600, s.Epoch(), s.BitLenTime())
+
+	want := time.Now().UTC().Truncate(time.Hour).Format("2006-01-02T15:04:05Z")
+	if key != want {
+		t.Errorf("key = %s, want %s", key, want)
+	}
+
+	bucket := time.Now().Unix() / 3600
+	minID, maxID := BucketRange(bucket, 3600, s.Epoch(), s.BitLenTime())
+	if id < minID || id > maxID {
+		t.Errorf("id %d not in bucket range [%d, %d]", id, minID, maxID)
+	}
+}
+
+func TestIDToTimeBucket(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		testIDToTimeBucket(t, opts...)
+	}
+}
+
+func testIDToTimeBucket(t *testing.T, opts ...Option) {
+	s, err := NewSnowflake(opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	const size = 60 * 1000
+
+	id := next(t, s)
+	if bucket := s.IDToTimeBucket(id, size); bucket != time.Now().UnixMilli()/size && bucket != time.Now().UnixMilli()/size-1 {
+		t.Errorf("bucket %d is not the current minute", bucket)
+	}
+	bucket := s.IDToTimeBucket(id, size)
+
+	minID, maxID := s.BucketToIDRange(bucket, size)
+	if id < minID || id > maxID {
+		t.Errorf("id %d not in [%d, %d]", id, minID, maxID)
+	}
+	if s.IDToTimeBucket(minID, size) != bucket || s.IDToTimeBucket(maxID, size) != bucket {
+		t.Error("range bounds should map back to the same bucket")
+	}
+	// 倒序时时间越晚 id 越小，范围之外的下一个桶在 minID 之前
+	after := maxID + 1
+	if s.descending {
+		after = minID - 1
+	}
+	if s.IDToTimeBucket(after, size) != bucket+1 {
+		t.Error("id outside the range should be in the next bucket")
+	}
+}
+
+func TestAggregationKey(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		s, err := NewSnowflake(append([]Option{WithWorkID(func() (int64, error) { return 7, nil })}, opts...)...)
+		if err != nil {
+			panic(err)
+		}
+
+		id := next(t, s)
+		timeKey, workerKey := s.AggregationKey(id, 1000, 4)
+		if want := s.TimeFromID(id).UnixMilli() / 1000; timeKey != want {
+			t.Errorf("timeKey = %d, want %d", timeKey, want)
+		}
+		if workerKey != 3 {
+			t.Errorf("workerKey = %d, want 3", workerKey)
+		}
+	}
+
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+	for _, c := range [][2]int64{{0, 4}, {1000, 0}, {-1, -1}} {
+		if timeKey, workerKey := s.AggregationKey(1, c[0], c[1]); timeKey != -1 || workerKey != -1 {
+			t.Errorf("AggregationKey(1, %d, %d) = %d, %d, want -1, -1", c[0], c[1], timeKey, workerKey)
+		}
+	}
+}
+
+func TestTimeBucket(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		testTimeBucket(t, opts...)
+	}
+}
+
+func testTimeBucket(t *testing.T, opts ...Option) {
+	s, err := NewSnowflake(append([]Option{WithEpoch(1288834974657)}, opts...)...)
+	if err != nil {
+		panic(err)
+	}
+
+	const size = 1000
+
+	// TimeBucket(id, size) == b 当且仅当 id 在 BucketRange(b, size) 中
+	before := time.Now().UnixMilli() / size
+	base := next(t, s)
+	b := s.TimeBucket(base, size)
+	if after := time.Now().UnixMilli() / size; b < before || b > after {
+		t.Fatalf("bucket %d not in [%d, %d]", b, before, after)
+	}
+	for _, bucket := range []int64{b - 1, b, b + 1} {
+		minID, maxID := s.BucketRange(bucket, size)
+
+		for _, id := range []int64{base - 3*size<<22, minID - 1, minID, base, maxID, maxID + 1, base + 3*size<<22} {
+			in := id >= minID && id <= maxID
+			if got := s.TimeBucket(id, size) == bucket; got != in {
+				t.Errorf("bucket %d, id %d: TimeBucket match = %v, in range = %v", bucket, id, got, in)
+			}
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package snowflake
+
+// maxWorkerID 默认结构下 workerID 的最大值
+const maxWorkerID = 1<<bitLenWorkerID - 1
+
+// ProcessGroupWorkerID 使用当前进程组 id 作为 workerID，截取为默认结构下 workerID 的长度
+// 同一台机器上部署多个进程（比如在本地负载均衡之后）时，由 ip 得到的 workerID 相同，而 Linux 上同一个会话中的进程组 id 不同
+// 注意进程组 id 会被复用，不相关的会话之间可能得到相同的 workerID；只支持类 Unix 系统，其它系统返回错误
+func ProcessGroupWorkerID() WorkerID {
+	return func() (int64, error) {
+		pgid, err := getpgrp()
+		if err != nil {
+			return 0, err
+		}
+		return int64(pgid) & maxWorkerID, nil
+	}
+}
+
+// WithProcessGroupWorkerID 使用进程组 id 作为 workerID，截取为实例配置的 workerID 长度，见 ProcessGroupWorkerID
+func WithProcessGroupWorkerID() Option {
+	return func(s *Snowflake) {
+		s.w = func() (int64, error) {
+			pgid, err := getpgrp()
+			if err != nil {
+				return 0, err
+			}
+			return int64(pgid) & (1<<s.bitLenWorkerID - 1), nil
+		}
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+
+package snowflake
+
+import "errors"
+
+// getpgrp 当前系统不支持进程组
+func getpgrp() (int, error) {
+	return 0, errors.New("snowflake: process group id is not supported on this platform")
+}
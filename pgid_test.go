@@ -0,0 +1,26 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package snowflake
+
+import (
+	"syscall"
+	"testing"
+)
+
+func TestProcessGroupWorkerID(t *testing.T) {
+	w, err := ProcessGroupWorkerID()()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(syscall.Getpgrp()) & maxWorkerID; w != want {
+		t.Errorf("worker id = %d, want %d", w, want)
+	}
+
+	s, err := NewSnowflake(WithLen(41, 8, 14), WithProcessGroupWorkerID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := int64(syscall.Getpgrp()) & (1<<8 - 1); s.WorkerID() != want {
+		t.Errorf("worker id = %d, want %d", s.WorkerID(), want)
+	}
+}
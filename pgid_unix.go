@@ -0,0 +1,10 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package snowflake
+
+import "syscall"
+
+// getpgrp 返回当前进程组 id
+func getpgrp() (int, error) {
+	return syscall.Getpgrp(), nil
+}
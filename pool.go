@@ -0,0 +1,147 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrClosed 实例已经关闭
+var ErrClosed = errors.New("snowflake: closed")
+
+var (
+	_ io.Closer = (*Snowflake)(nil)
+	_ io.Closer = (*Pool)(nil)
+)
+
+// Close 关闭实例，之后 NextID 等方法返回 ErrClosed
+func (s *Snowflake) Close() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.closed = true
+
+	return nil
+}
+
+// Pool 多个雪花算法实例组成的池，轮流使用各个分片生成 id，减少单个实例的锁竞争
+// 每个分片的 workerID 必须不同
+type Pool struct {
+	// 分片
+	shards []*Snowflake
+	// 下一次使用的分片
+	next uint64
+	// 是否已经关闭
+	closed int32
+}
+
+// NewPool 使用多个实例新建一个池，实例的 workerID 不能重复
+func NewPool(shards ...*Snowflake) (*Pool, error) {
+	if len(shards) == 0 {
+		return nil, errors.New("snowflake: pool needs at least one shard")
+	}
+
+	seen := make(map[int64]bool)
+	for _, s := range shards {
+		if seen[s.workerID] {
+			return nil, fmt.Errorf("snowflake: duplicate worker id %d in pool", s.workerID)
+		}
+		seen[s.workerID] = true
+	}
+
+	return &Pool{shards: shards}, nil
+}
+
+// NextID 使用下一个分片生成 id
+// 注意 Close 之后再调用会 panic
+func (p *Pool) NextID() (int64, error) {
+	if atomic.LoadInt32(&p.closed) == 1 {
+		panic("snowflake: NextID called on closed pool")
+	}
+
+	i := atomic.AddUint64(&p.next, 1) - 1
+	return p.shards[i%uint64(len(p.shards))].NextID()
+}
+
+// Close 关闭所有分片，释放资源，返回所有分片关闭时的错误
+// 之后再调用 NextID 会 panic
+func (p *Pool) Close() error {
+	atomic.StoreInt32(&p.closed, 1)
+
+	var errs multiError
+	for _, s := range p.shards {
+		if err := s.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// CloneWithWorkerID 复制当前实例的配置，创建一个 workerID 为 newWorkerID 的新实例，用于从模板实例构造 Pool 的成员
+// 复制 epoch、各部分长度、时钟、日志等所有配置，但不复制生成状态（序列号、lastTime 等）和耗时统计
+// newWorkerID 超出 workerID 部分的范围时返回错误
+func (s *Snowflake) CloneWithWorkerID(newWorkerID int64) (*Snowflake, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	c := &Snowflake{
+		lastTime: epoch,
+		w: func() (int64, error) {
+			return newWorkerID, nil
+		},
+		clock:                s.clock,
+		maxWait:              s.maxWait,
+		maxRetries:           s.maxRetries,
+		clockBackwardTimeout: s.clockBackwardTimeout,
+		logger:               s.logger,
+		nonIncrement:         s.nonIncrement,
+		epoch:                s.epoch,
+		bitLenTime:           s.bitLenTime,
+		bitLenWorkerID:       s.bitLenWorkerID,
+		bitLenSequence:       s.bitLenSequence,
+		sequenceMask:         s.sequenceMask,
+		sequenceOffset:       s.sequenceOffset,
+		entropy:              s.entropy,
+		randomSequence:       s.randomSequence,
+		descending:           s.descending,
+		tag:                  s.tag,
+		tagBits:              s.tagBits,
+		priorityBits:         s.priorityBits,
+		checksumBits:         s.checksumBits,
+		region:               s.region,
+		regionBits:           s.regionBits,
+		groupBits:            s.groupBits,
+		entityKindBits:       s.entityKindBits,
+		entityIDBits:         s.entityIDBits,
+	}
+
+	c.workerID = newWorkerID
+	if c.regionBits > 0 && c.regionBits < c.bitLenWorkerID {
+		c.workerID |= c.region << (c.bitLenWorkerID - c.regionBits)
+	}
+
+	for _, i := range c.check() {
+		if i.fatal {
+			return nil, errors.New("snowflake: " + i.msg)
+		}
+	}
+
+	return c, nil
+}
+
+// multiError 多个错误
+type multiError []error
+
+func (m multiError) Error() string {
+	msgs := make([]string, len(m))
+	for i, err := range m {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func newPool(t *testing.T, n int) *Pool {
+	t.Helper()
+
+	var shards []*Snowflake
+	for i := 0; i < n; i++ {
+		w := int64(i)
+		s, err := NewSnowflake(WithWorkID(func() (int64, error) { return w, nil }))
+		if err != nil {
+			t.Fatal(err)
+		}
+		shards = append(shards, s)
+	}
+
+	p, err := NewPool(shards...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestPool(t *testing.T) {
+	p := newPool(t, 4)
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := p.NextID()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+	}
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.shards[0].NextID(); err != ErrClosed {
+		t.Errorf("err = %v, want ErrClosed", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic after Close")
+		}
+	}()
+	_, _ = p.NextID()
+}
+
+func TestNewPoolDuplicateWorker(t *testing.T) {
+	s, _ := NewSnowflake()
+	if _, err := NewPool(s, s); err == nil {
+		t.Error("expected error for duplicate worker id")
+	}
+	if _, err := NewPool(); err == nil {
+		t.Error("expected error for empty pool")
+	}
+}
+
+func TestCloneWithWorkerID(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour).UnixMilli()
+	tmpl, err := NewSnowflake(
+		WithEpoch(epoch),
+		WithLen(41, 14, 8),
+		WithWorkID(func() (int64, error) { return 0, nil }),
+		WithNonIncrement(),
+	)
+	if err != nil {
+		panic(err)
+	}
+	next(t, tmpl)
+
+	var shards []*Snowflake
+	for i := int64(1); i <= 3; i++ {
+		c, err := tmpl.CloneWithWorkerID(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.WorkerID() != i || c.Layout() != tmpl.Layout() || c.Epoch() != epoch {
+			t.Errorf("clone %d: worker %d, layout %+v, epoch %d", i, c.WorkerID(), c.Layout(), c.Epoch())
+		}
+		if c.LastID() != 0 {
+			t.Error("clone should not copy the generator state")
+		}
+		shards = append(shards, c)
+	}
+
+	if _, err = NewPool(shards...); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = tmpl.CloneWithWorkerID(1 << 14); err == nil {
+		t.Error("expected error for worker id out of range")
+	}
+}
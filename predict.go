@@ -0,0 +1,26 @@
+package snowflake
+
+// PredictNextID 预测下一次 NextID 会返回的 id，不改变序列号、lastTime 等状态，用于乐观锁中提前知道下一个 id
+// 实现为在加锁的情况下生成一个 id，再恢复生成之前的状态
+//
+// 注意：
+// 1. 只要有任何并发的 NextID 调用，或者时钟前进到了下一毫秒，预测结果就失效了，必须以之后真正生成的 id 为准
+// 2. 设置了 WithEntropySource、WithRandomSequence 时序列号不可预测，结果没有意义
+// 3. 无法生成 id 时（比如时间回拨）返回 0
+func (s *Snowflake) PredictNextID() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lastID, lastTime, t := s.lastID, s.lastTime, s.time
+	sequenceID, sequenceStart := s.sequenceID, s.sequenceStart
+	defer func() {
+		s.lastID, s.lastTime, s.time = lastID, lastTime, t
+		s.sequenceID, s.sequenceStart = sequenceID, sequenceStart
+	}()
+
+	id, err := s.nextID()
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,32 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPredictNextID(t *testing.T) {
+	now := time.Now().UnixMilli()
+	s, err := NewSnowflake(WithClock(func() int64 { return now }))
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		last := s.LastID()
+		predicted := s.PredictNextID()
+		if s.LastID() != last {
+			t.Fatal("prediction should not change the state")
+		}
+		if id := next(t, s); id != predicted {
+			t.Fatalf("predicted %d, got %d", predicted, id)
+		}
+	}
+
+	// 进入下一毫秒
+	now++
+	predicted := s.PredictNextID()
+	if id := next(t, s); id != predicted {
+		t.Errorf("predicted %d, got %d", predicted, id)
+	}
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithPriorityBits 自定义优先级的 bit 长度，优先级放在序列号的高 n 位，用于 NextPriorityID
+// 设置了 WithTypeTag 时放在类型标签之下，每毫秒可用的序列号减少为 1/2^n
+func WithPriorityBits(n int) Option {
+	return func(s *Snowflake) {
+		s.priorityBits = int64(n)
+	}
+}
+
+// NextPriorityID 生成一个带优先级的 id，用于同一毫秒内高优先级的事件先处理的队列
+// priority 越小，序列号越小，在按 id 升序处理的队列中越先被处理，普通的 NextID 相当于优先级 0
+// 需要先通过 WithPriorityBits 设置优先级的长度
+func (s *Snowflake) NextPriorityID(priority uint8) (int64, error) {
+	if s.priorityBits == 0 {
+		return 0, errors.New("snowflake: priority bits not configured, use WithPriorityBits")
+	}
+	if int64(priority) >= 1<<s.priorityBits {
+		return 0, fmt.Errorf("snowflake: priority %d does not fit in %d bits", priority, s.priorityBits)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.priority = int64(priority)
+	defer func() {
+		s.priority = 0
+	}()
+
+	return s.nextID()
+}
+
+// ParsePriority 取出 NextPriorityID 生成的 id 的优先级，即序列号的高 priorityBits 位
+func ParsePriority(id int64, layout BitLayout, priorityBits int) uint8 {
+	_, _, seq := layout.Decompose(id)
+	return uint8(seq >> (layout.Sequence - int64(priorityBits)))
+}
@@ -0,0 +1,41 @@
+package snowflake
+
+import "testing"
+
+func TestNextPriorityID(t *testing.T) {
+	s, err := NewSnowflake(WithPriorityBits(3))
+	if err != nil {
+		panic(err)
+	}
+
+	high, err := s.NextPriorityID(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	low, err := s.NextPriorityID(5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p := ParsePriority(low, s.Layout(), 3); p != 5 {
+		t.Errorf("priority = %d, want 5", p)
+	}
+	if p := ParsePriority(next(t, s), s.Layout(), 3); p != 0 {
+		t.Errorf("NextID priority = %d, want 0", p)
+	}
+	if _, _, hs := s.decompose(high); hs >= 5<<(s.BitLenSequence()-3) {
+		t.Error("higher priority should have a lower sequence")
+	}
+
+	if _, err = s.NextPriorityID(8); err == nil {
+		t.Error("expected error for priority out of range")
+	}
+
+	plain, _ := NewSnowflake()
+	if _, err = plain.NextPriorityID(0); err == nil {
+		t.Error("expected error without WithPriorityBits")
+	}
+	if _, err = NewSnowflake(WithTypeTag(1, 5), WithPriorityBits(5)); err == nil {
+		t.Error("expected error when tag and priority use all sequence bits")
+	}
+}
@@ -0,0 +1,26 @@
+package snowflake
+
+import "github.com/edte/snowflake/snowflakeproto/snowflakepb"
+
+// NextIDAsProto 生成一个 id，并转换为 protobuf 消息，用于 gRPC 接口中传递有类型的 id
+// 除了完整的 id，还按照本实例的结构填充生成时间（Unix 毫秒时间戳）、workerID 和序列号
+func (s *Snowflake) NextIDAsProto() (*snowflakepb.SnowflakeID, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return nil, err
+	}
+
+	_, w, seq := s.decompose(id)
+
+	return &snowflakepb.SnowflakeID{
+		Value:      id,
+		TimeMs:     s.DecodeTimestamp(id),
+		WorkerId:   w,
+		SequenceId: seq,
+	}, nil
+}
+
+// FromProto 取出 protobuf 消息中的 id，是 NextIDAsProto 的逆运算，pb 为 nil 时返回 0
+func FromProto(pb *snowflakepb.SnowflakeID) int64 {
+	return pb.GetValue()
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/edte/snowflake/snowflakeproto/snowflakepb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNextIDAsProto(t *testing.T) {
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 5, nil }))
+	if err != nil {
+		panic(err)
+	}
+
+	pb, err := s.NextIDAsProto()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts, w, seq := s.Parse(uint64(pb.GetValue()))
+	if pb.GetTimeMs() != int64(ts) || pb.GetWorkerId() != 5 || pb.GetSequenceId() != int64(seq) || w != 5 {
+		t.Errorf("proto %v does not match id %d", pb, pb.GetValue())
+	}
+
+	// 序列化后再解析，id 不变
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got snowflakepb.SnowflakeID
+	if err = proto.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if id := FromProto(&got); id != pb.GetValue() {
+		t.Errorf("FromProto = %d, want %d", id, pb.GetValue())
+	}
+
+	if id := FromProto(nil); id != 0 {
+		t.Errorf("FromProto(nil) = %d, want 0", id)
+	}
+}
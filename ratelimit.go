@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRateLimitExceeded 超过了分布式限流的限制
+var ErrRateLimitExceeded = errors.New("snowflake: rate limit exceeded")
+
+// DistributedLimiter 分布式限流器，比如基于 Redis 的 go-redis/redis_rate
+type DistributedLimiter interface {
+	// Allow 是否允许这一次请求
+	Allow(ctx context.Context) (bool, error)
+}
+
+// rateWindow 限流的时间窗口
+type rateWindow struct {
+	// 窗口大小，单位毫秒
+	size int64
+	// 第几个窗口
+	index int64
+}
+
+// NextRateLimitToken 生成一个 id，同时统计 id 所在时间窗口内生成的次数
+// 时间窗口由 id 的时间部分除以 windowMs 得到，underLimit 表示这个窗口内的次数是否没有超过 limit
+// 超过限制时仍然会生成 id，是否拒绝请求由调用方决定
+// 进入新的窗口时，同样大小的旧窗口的计数会被清理
+func (s *Snowflake) NextRateLimitToken(windowMs, limit int64) (id int64, underLimit bool, err error) {
+	if windowMs <= 0 {
+		return 0, false, errors.New("snowflake: window must be positive")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if id, err = s.nextID(); err != nil {
+		return 0, false, err
+	}
+
+	w := rateWindow{size: windowMs, index: s.time / windowMs}
+	if s.rateCounts == nil {
+		s.rateCounts = make(map[rateWindow]int64)
+	}
+	if _, ok := s.rateCounts[w]; !ok {
+		for old := range s.rateCounts {
+			if old.size == w.size && old.index < w.index {
+				delete(s.rateCounts, old)
+			}
+		}
+	}
+	s.rateCounts[w]++
+
+	return id, s.rateCounts[w] <= limit, nil
+}
+
+// NextIDWithGlobalRate 先通过分布式限流器检查，允许时才生成 id，用于跨进程的限流
+// 限流器返回 false 时返回 ErrRateLimitExceeded，返回错误时直接返回这个错误
+// 限流器的调用不持有锁，不会阻塞其它 goroutine 生成 id
+func (s *Snowflake) NextIDWithGlobalRate(ctx context.Context, dl DistributedLimiter) (int64, error) {
+	ok, err := dl.Allow(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrRateLimitExceeded
+	}
+
+	return s.NextID()
+}
+
+// NextWindowID 生成一个 id，同时返回这个 id 所在时间窗口的 id，用于限流的计数桶
+// windowID 的时间部分为绝对时间向下取整到 windowSizeMs 的边界，其它部分为 0，同一窗口内的请求得到相同的 windowID，可以作为 Redis 的 key
+// uniqueID 为完整的 id，用于标识这一次请求
+// 窗口边界早于 epoch 时，windowID 的时间部分取 0
+func (s *Snowflake) NextWindowID(windowSizeMs int64) (windowID, uniqueID int64, err error) {
+	if windowSizeMs <= 0 {
+		return 0, 0, errors.New("snowflake: window must be positive")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if uniqueID, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+
+	abs := s.time + s.epoch
+	t := abs/windowSizeMs*windowSizeMs - s.epoch
+	if t < 0 {
+		t = 0
+	}
+
+	return s.compose(t, 0, 0), uniqueID, nil
+}
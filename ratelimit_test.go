@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNextRateLimitToken(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	// 一个足够大的窗口，测试期间不会切换
+	const window = 1 << 40
+
+	for i := 0; i < 5; i++ {
+		_, ok, err := s.NextRateLimitToken(window, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != (i < 3) {
+			t.Errorf("call %d: underLimit = %v", i, ok)
+		}
+	}
+
+	if _, _, err = s.NextRateLimitToken(0, 1); err == nil {
+		t.Error("expected error for zero window")
+	}
+}
+
+// countLimiter 允许前 n 次请求的限流器
+type countLimiter struct {
+	n int
+}
+
+func (l *countLimiter) Allow(ctx context.Context) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	l.n--
+	return l.n >= 0, nil
+}
+
+func TestNextIDWithGlobalRate(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	l := &countLimiter{n: 2}
+	for i := 0; i < 3; i++ {
+		_, err = s.NextIDWithGlobalRate(context.Background(), l)
+		if i < 2 && err != nil {
+			t.Fatal(err)
+		}
+		if i == 2 && err != ErrRateLimitExceeded {
+			t.Errorf("err = %v, want ErrRateLimitExceeded", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = s.NextIDWithGlobalRate(ctx, l); err != context.Canceled {
+		t.Errorf("err = %v, want context.Canceled", err)
+	}
+}
+
+func TestNextWindowID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	window, id, err := s.NextWindowID(1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != s.LastID() {
+		t.Errorf("unique id = %d, want %d", id, s.LastID())
+	}
+
+	wt, ww, wseq := s.decompose(window)
+	it, _, _ := s.decompose(id)
+	if ww != 0 || wseq != 0 {
+		t.Errorf("window id %d should only have time bits", window)
+	}
+	if (wt+s.epoch)%1000 != 0 || wt > it || it-wt >= 1000 {
+		t.Errorf("window time %d does not contain id time %d", wt, it)
+	}
+
+	if _, _, err = s.NextWindowID(0); err == nil {
+		t.Error("expected error for zero window")
+	}
+}
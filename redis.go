@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// IDToRedisStreamID 把 id 转换为 Redis stream 的消息 id 格式 <ms>-<seq>
+// ms 为 id 的绝对毫秒时间，seq 为序列号，比如 1609459200000-1，可以直接用于 XADD mystream <id> ...
+// 注意 workerID 部分会丢失，多个 worker 写入同一个 stream 时可能重复
+func (s *Snowflake) IDToRedisStreamID(id int64) string {
+	_, _, seq := s.decompose(id)
+	return strconv.FormatInt(s.DecodeTimestamp(id), 10) + "-" + strconv.FormatInt(seq, 10)
+}
+
+// ParseRedisStreamID 把 Redis stream 的消息 id 转换回 id，workerID 部分为 0
+// 时间部分按照自增的结构保存，不会取反，即使原来的 id 设置了倒序
+func ParseRedisStreamID(s string, layout BitLayout, epoch int64) (int64, error) {
+	i := strings.IndexByte(s, '-')
+	if i < 0 {
+		return 0, fmt.Errorf("snowflake: invalid redis stream id %q", s)
+	}
+
+	ms, err := strconv.ParseInt(s[:i], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("snowflake: invalid redis stream id %q: %w", s, err)
+	}
+	seq, err := strconv.ParseInt(s[i+1:], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("snowflake: invalid redis stream id %q: %w", s, err)
+	}
+
+	t := ms - epoch
+	if t < 0 || t >= 1<<layout.Time {
+		return 0, fmt.Errorf("snowflake: redis stream id %q time out of range", s)
+	}
+	if seq < 0 || seq >= 1<<layout.Sequence {
+		return 0, fmt.Errorf("snowflake: redis stream id %q sequence out of range", s)
+	}
+
+	return layout.Compose(t, 0, seq), nil
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRedisStreamID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	sid := s.IDToRedisStreamID(id)
+
+	got, err := ParseRedisStreamID(sid, s.Layout(), s.Epoch())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// workerID 部分为 0，其它部分不变
+	want := id &^ (((1 << s.BitLenWorkerID()) - 1) << s.BitLenSequence())
+	if got != want {
+		t.Errorf("got %d, want %d", got, want)
+	}
+
+	// 倒序时 ms 仍然是 id 的生成时间
+	s, err = NewSnowflake(WithDescendingSequence())
+	if err != nil {
+		panic(err)
+	}
+	id = next(t, s)
+	if sid, want := s.IDToRedisStreamID(id), strconv.FormatInt(s.TimeFromID(id).UnixMilli(), 10)+"-"; !strings.HasPrefix(sid, want) {
+		t.Errorf("stream id %s, want prefix %s", sid, want)
+	}
+
+	for _, bad := range []string{"", "123", "a-1", "1-b", "1-100000"} {
+		if _, err := ParseRedisStreamID(bad, s.Layout(), s.Epoch()); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithRegionID 自定义区域 id，放在 workerID 部分的高 regionBits 位，用于多区域双活部署
+// 不同区域在同一毫秒生成的 id 也不会重复，剩下的低位为机器 id，WithWorkID 得到的 workerID 必须能放进低位
+// 要求 regionBits < bitLenWorkerID，regionID < 1<<regionBits，否则 NewSnowflake 返回错误
+func WithRegionID(regionID, regionBits int64) Option {
+	return func(s *Snowflake) {
+		s.region = regionID
+		s.regionBits = regionBits
+	}
+}
+
+// NextIDForRegion 生成一个 id，workerID 部分的区域 id 替换为 regionID，机器 id 不变
+// 用于代替其它区域生成 id，比如故障转移期间；需要先通过 WithRegionID 设置区域 id 的长度
+// 注意替换后可能和 regionID 区域中机器 id 相同的实例生成的 id 重复
+func (s *Snowflake) NextIDForRegion(regionID int64) (int64, error) {
+	if s.regionBits == 0 {
+		return 0, errors.New("snowflake: region bits not configured, use WithRegionID")
+	}
+	if regionID < 0 || regionID >= 1<<s.regionBits {
+		return 0, fmt.Errorf("snowflake: region id %d does not fit in %d bits", regionID, s.regionBits)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return 0, err
+	}
+
+	shift := s.bitLenWorkerID - s.regionBits
+	t, w, seq := s.decompose(id)
+	return s.compose(t, regionID<<shift|w&(1<<shift-1), seq), nil
+}
+
+// RegionFromID 取出 WithRegionID 设置的区域 id，即 workerID 部分的高 regionBits 位
+func RegionFromID(id int64, layout BitLayout, regionBits int64) int64 {
+	_, w, _ := layout.Decompose(id)
+	return w >> (layout.WorkerID - regionBits)
+}
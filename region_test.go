@@ -0,0 +1,47 @@
+package snowflake
+
+import "testing"
+
+func TestWithRegionID(t *testing.T) {
+	machine := WithWorkID(func() (int64, error) { return 5, nil })
+
+	s, err := NewSnowflake(machine, WithRegionID(2, 3))
+	if err != nil {
+		panic(err)
+	}
+
+	id := next(t, s)
+	if got := RegionFromID(id, s.Layout(), 3); got != 2 {
+		t.Errorf("region = %d, want 2", got)
+	}
+
+	other, err := s.NextIDForRegion(6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RegionFromID(other, s.Layout(), 3); got != 6 {
+		t.Errorf("region = %d, want 6", got)
+	}
+	if _, w, _ := s.decompose(other); w&(1<<(s.bitLenWorkerID-3)-1) != 5 {
+		t.Errorf("machine id = %d, want 5", w&(1<<(s.bitLenWorkerID-3)-1))
+	}
+
+	if _, err = s.NextIDForRegion(8); err == nil {
+		t.Error("expected error for region out of range")
+	}
+
+	plain, _ := NewSnowflake(machine)
+	if _, err = plain.NextIDForRegion(1); err == nil {
+		t.Error("expected error without region bits")
+	}
+
+	for _, opts := range [][]Option{
+		{machine, WithRegionID(8, 3)},
+		{machine, WithRegionID(1, 12)},
+		{WithWorkID(func() (int64, error) { return 1 << 10, nil }), WithRegionID(1, 3)},
+	} {
+		if _, err = NewSnowflake(opts...); err == nil {
+			t.Error("expected error for invalid region configuration")
+		}
+	}
+}
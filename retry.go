@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Logger 日志接口，*slog.Logger 满足这个接口
+type Logger interface {
+	// Debug 打印调试日志，args 为交替的 key、value
+	Debug(msg string, args ...interface{})
+}
+
+// WithLogger 自定义日志，用于打印重试等调试信息，默认不打印
+func WithLogger(l Logger) Option {
+	return func(s *Snowflake) {
+		s.logger = l
+	}
+}
+
+// debug 打印调试日志，没有设置日志时忽略
+func (s *Snowflake) debug(msg string, args ...interface{}) {
+	if s.logger != nil {
+		s.logger.Debug(msg, args...)
+	}
+}
+
+// NextIDContext 生成下一个 id，ctx 已经取消时直接返回 ctx 的错误
+// 时间回拨时需要等待 WithClockBackwardTimeout 设置的时间，如果等待会超过 ctx 的截止时间，则直接返回 context.DeadlineExceeded
+func (s *Snowflake) NextIDContext(ctx context.Context) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.lastTime > s.clock() {
+		if d, ok := ctx.Deadline(); ok && time.Until(d) < s.clockBackwardTimeout {
+			return 0, context.DeadlineExceeded
+		}
+	}
+
+	return s.nextID()
+}
+
+// NextIDWithDeadline 生成下一个 id，时间回拨的等待会超过 deadline 时返回 context.DeadlineExceeded，用于有严格 SLA 的系统
+// 等价于使用 context.WithDeadline 调用 NextIDContext
+func (s *Snowflake) NextIDWithDeadline(deadline time.Time) (int64, error) {
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	return s.NextIDContext(ctx)
+}
+
+// NextIDWithRetry 生成下一个 id，时间回拨（ErrTimeBackward）时最多重试 maxRetries 次，用于 NTP 不稳定、可能连续回拨的环境
+// 每次重试之前指数退避，从 1ms 开始翻倍，最长为 WithClockBackwardTimeout 设置的等待时间，每次重试都会打印调试日志
+// 其它错误、ctx 取消、重试次数用完时返回错误
+func (s *Snowflake) NextIDWithRetry(ctx context.Context, maxRetries int) (int64, error) {
+	backoff := time.Millisecond
+
+	for attempt := 0; ; attempt++ {
+		id, err := s.NextIDContext(ctx)
+		if !errors.Is(err, ErrTimeBackward) || attempt >= maxRetries {
+			return id, err
+		}
+
+		if backoff > s.clockBackwardTimeout {
+			backoff = s.clockBackwardTimeout
+		}
+		s.debug("snowflake: clock moved backwards, retrying", "attempt", attempt+1, "backoff", backoff)
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return 0, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
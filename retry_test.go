@@ -0,0 +1,94 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// debugLogger 记录调试日志的 Logger
+type debugLogger struct {
+	msgs []string
+}
+
+func (l *debugLogger) Debug(msg string, args ...interface{}) {
+	l.msgs = append(l.msgs, fmt.Sprint(append([]interface{}{msg}, args...)...))
+}
+
+// backwardClock 返回一个时钟，前 n 次调用比 base 慢，之后比 base 快
+func backwardClock(base int64, n int) Clock {
+	return func() int64 {
+		if n > 0 {
+			n--
+			return base - 10
+		}
+		return base + 1
+	}
+}
+
+func TestNextIDWithRetry(t *testing.T) {
+	base := time.Now().UnixMilli()
+	clock := backwardClock(base, 0)
+	log := &debugLogger{}
+
+	s, err := NewSnowflake(
+		WithClock(func() int64 { return clock() }),
+		WithClockBackwardTimeout(time.Millisecond),
+		WithLogger(log),
+	)
+	if err != nil {
+		panic(err)
+	}
+	s.SetLastTime(base)
+
+	// 每次失败的生成会调用三次时钟，前两次失败，第三次成功
+	clock = backwardClock(base, 6)
+	if _, err = s.NextIDWithRetry(context.Background(), 5); err != nil {
+		t.Fatal(err)
+	}
+	if len(log.msgs) != 2 {
+		t.Errorf("got %d debug logs, want 2", len(log.msgs))
+	}
+
+	clock = backwardClock(base+10, 100)
+	s.SetLastTime(base + 10)
+	if _, err = s.NextIDWithRetry(context.Background(), 2); err != ErrTimeBackward {
+		t.Errorf("err = %v, want %v", err, ErrTimeBackward)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = s.NextIDWithRetry(ctx, 2); err != context.Canceled {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestNextIDWithDeadline(t *testing.T) {
+	base := time.Now().UnixMilli()
+	clock := backwardClock(base, 0)
+
+	s, err := NewSnowflake(WithClock(func() int64 { return clock() }))
+	if err != nil {
+		panic(err)
+	}
+
+	if _, err = s.NextIDWithDeadline(time.Now().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 时间回拨需要等待 1s，超过了截止时间
+	s.SetLastTime(base + 10)
+	clock = backwardClock(base, 100)
+	start := time.Now()
+	if _, err = s.NextIDWithDeadline(time.Now().Add(10 * time.Millisecond)); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Error("should return without waiting for the clock")
+	}
+
+	if _, err = s.NextIDWithDeadline(time.Now().Add(-time.Second)); err != context.DeadlineExceeded {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,54 @@
+package snowflake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+)
+
+// sessionSuffixLen 会话 token 后缀的长度
+const sessionSuffixLen = 4
+
+// NextSessionToken 生成一个和用户关联的会话 token，唯一且按时间排序，用于内部服务
+// 结构为 base-62 编码的 id，加上 4 个字符的后缀，后缀为 fnv32(userID) % 36^4 的 base-36 编码
+// 注意这不是安全 token，id 可以被猜到，后缀也只是用户的弱标识
+func (s *Snowflake) NextSessionToken(userID int64) (string, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return "", err
+	}
+
+	suffix := strconv.FormatUint(uint64(sessionSuffix(userID)), 36)
+	return Base62.Encode(id) + strings.Repeat("0", sessionSuffixLen-len(suffix)) + suffix, nil
+}
+
+// ParseSessionToken 解析 NextSessionToken 生成的 token，返回 id 和用户后缀
+func ParseSessionToken(s string) (id int64, userIDSuffix uint32, err error) {
+	if len(s) <= sessionSuffixLen {
+		return 0, 0, fmt.Errorf("snowflake: invalid session token %q", s)
+	}
+
+	i := len(s) - sessionSuffixLen
+	suffix, err := strconv.ParseUint(s[i:], 36, 32)
+	if err != nil {
+		return 0, 0, fmt.Errorf("snowflake: invalid session token %q: %w", s, err)
+	}
+	if id, err = Base62.Decode(s[:i]); err != nil {
+		return 0, 0, err
+	}
+
+	return id, uint32(suffix), nil
+}
+
+// SessionTokenMatchesUser 判断 token 的后缀是否和 userID 匹配
+func SessionTokenMatchesUser(userIDSuffix uint32, userID int64) bool {
+	return userIDSuffix == sessionSuffix(userID)
+}
+
+// sessionSuffix 计算 userID 对应的后缀
+func sessionSuffix(userID int64) uint32 {
+	h := fnv.New32()
+	_, _ = h.Write([]byte(strconv.FormatInt(userID, 10)))
+	return h.Sum32() % (36 * 36 * 36 * 36)
+}
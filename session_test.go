@@ -0,0 +1,32 @@
+package snowflake
+
+import "testing"
+
+func TestNextSessionToken(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	token, err := s.NextSessionToken(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, suffix, err := ParseSessionToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != s.LastID() {
+		t.Errorf("id = %d, want %d", id, s.LastID())
+	}
+	if !SessionTokenMatchesUser(suffix, 42) {
+		t.Error("suffix should match the user")
+	}
+
+	for _, bad := range []string{"", "abcd", "ab!cd0000", "abc$$$$"} {
+		if _, _, err = ParseSessionToken(bad); err == nil {
+			t.Errorf("%q: expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+)
+
+// JumpConsistentHashShard 使用 Jump Consistent Hash（Lamping & Veach, 2014）计算 id 所在的分片
+// 只使用 id 的 workerID 和序列号部分（按默认结构），不使用时间部分，保证同一个 id 的分片稳定
+// 和取模分片不同，分片数从 n 变为 n+1 时，只有约 1/(n+1) 的 id 需要迁移，减少分片时同理
+// numShards 小于等于 0 时返回 -1
+func JumpConsistentHashShard(id int64, numShards int) int {
+	if numShards <= 0 {
+		return -1
+	}
+
+	key := uint64(id) & (1<<(bitLenWorkerID+bitLenSequence) - 1)
+
+	var b, j int64 = -1, 0
+	for j < int64(numShards) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+
+	return int(b)
+}
+
+// ShardForID 计算 id 所在的分片，为 id 按无符号整数对 numShards 取模
+// numShards 小于等于 0 时返回 -1
+func ShardForID(id int64, numShards int) int {
+	if numShards <= 0 {
+		return -1
+	}
+	return int(uint64(id) % uint64(numShards))
+}
+
+// NextIDForShard 生成一个落在 shardID 分片上的 id，用于预先分片的数据库中把同一个用户的数据放在一起
+// workerID 固定不变，因此不断推进序列号，直到 ShardForID(id, numShards) == shardID，
+// 默认结构下序列号每次加 1，最多需要 numShards 次；超过最大重试次数则返回 ErrMaxRetriesExceeded
+// 设置了 WithNonIncrement 时 id 的最低位是固定的 workerID，numShards 含有因子 2^k 时，
+// 只有和 workerID 对 2^min(k, workerID 长度) 同余的分片才能得到，其它分片直接返回错误
+func (s *Snowflake) NextIDForShard(shardID, numShards int) (int64, error) {
+	if numShards <= 0 || shardID < 0 || shardID >= numShards {
+		return 0, errors.New("snowflake: shard id out of range")
+	}
+	if s.nonIncrement {
+		k := int64(bits.TrailingZeros(uint(numShards)))
+		if k > s.bitLenWorkerID {
+			k = s.bitLenWorkerID
+		}
+		if mask := int64(1)<<k - 1; int64(shardID)&mask != s.workerID&mask {
+			return 0, fmt.Errorf("snowflake: shard %d of %d is unreachable, the low %d bits of ids are fixed by worker id %d", shardID, numShards, k, s.workerID)
+		}
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := 0; i <= s.maxRetries; i++ {
+		id, err := s.nextID()
+		if err != nil {
+			return 0, err
+		}
+		if ShardForID(id, numShards) == shardID {
+			return id, nil
+		}
+	}
+
+	return 0, ErrMaxRetriesExceeded
+}
+
+// ConsistentHashPosition 计算 id 在一致性哈希环上的位置，用于缓存路由
+// 只对 workerID 和序列号部分做 FNV-1a 哈希，不使用时间部分，环上的位置不会随时间变化
+// ringSize 小于等于 0 时返回 -1
+func ConsistentHashPosition(id int64, ringSize int64, layout BitLayout) int64 {
+	if ringSize <= 0 {
+		return -1
+	}
+
+	_, w, seq := layout.Decompose(id)
+	return int64(hashFields(uint64(w)<<layout.Sequence|uint64(seq)) % uint64(ringSize))
+}
+
+// hashFields 计算 v 的 FNV-1a 哈希
+func hashFields(v uint64) uint64 {
+	var b [8]byte
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write(b[:])
+	return h.Sum64()
+}
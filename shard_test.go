@@ -0,0 +1,135 @@
+package snowflake
+
+import "testing"
+
+func TestJumpConsistentHashShard(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	const n = 10000
+	moved := 0
+	for i := 0; i < n; i++ {
+		id := next(t, s)
+
+		a := JumpConsistentHashShard(id, 10)
+		if a < 0 || a >= 10 {
+			t.Fatalf("shard %d out of range", a)
+		}
+		if a != JumpConsistentHashShard(id, 10) {
+			t.Fatal("shard is not stable")
+		}
+
+		b := JumpConsistentHashShard(id, 11)
+		if a != b {
+			if b != 10 {
+				t.Fatalf("id moved from shard %d to existing shard %d", a, b)
+			}
+			moved++
+		}
+	}
+
+	// 理论上约 1/11 的 id 迁移到新分片
+	if moved == 0 || moved > n/5 {
+		t.Errorf("moved %d of %d ids", moved, n)
+	}
+
+	if JumpConsistentHashShard(1, 0) != -1 {
+		t.Error("expected -1 for zero shards")
+	}
+}
+
+func TestConsistentHashPosition(t *testing.T) {
+	layout := DefaultBitLayout
+
+	for i := int64(0); i < 1000; i++ {
+		a := layout.Compose(i, 3, i%7)
+		b := layout.Compose(i+12345, 3, i%7)
+
+		pos := ConsistentHashPosition(a, 64, layout)
+		if pos < 0 || pos >= 64 {
+			t.Fatalf("position %d out of range", pos)
+		}
+		if pos != ConsistentHashPosition(b, 64, layout) {
+			t.Fatal("position should not depend on the time bits")
+		}
+	}
+
+	if ConsistentHashPosition(1, 0, layout) != -1 {
+		t.Error("expected -1 for an empty ring")
+	}
+}
+
+// 对比只哈希 workerID 和序列号与哈希整个 id：两者开销相近，
+// 但整个 id 的位置每毫秒都会变化，moved/op 表示时间前进 1ms 后位置改变的比例
+func BenchmarkConsistentHashPosition(b *testing.B) {
+	layout := DefaultBitLayout
+	moved := 0
+	for i := 0; i < b.N; i++ {
+		id := layout.Compose(int64(i), 1, int64(i)&(1<<layout.Sequence-1))
+		if ConsistentHashPosition(id, 1024, layout) != ConsistentHashPosition(id+1<<(layout.WorkerID+layout.Sequence), 1024, layout) {
+			moved++
+		}
+	}
+	b.ReportMetric(float64(moved)/float64(b.N), "moved/op")
+}
+
+func BenchmarkConsistentHashPositionFullID(b *testing.B) {
+	layout := DefaultBitLayout
+	position := func(id int64) uint64 { return hashFields(uint64(id)) % 1024 }
+
+	moved := 0
+	for i := 0; i < b.N; i++ {
+		id := layout.Compose(int64(i), 1, int64(i)&(1<<layout.Sequence-1))
+		if position(id) != position(id+1<<(layout.WorkerID+layout.Sequence)) {
+			moved++
+		}
+	}
+	b.ReportMetric(float64(moved)/float64(b.N), "moved/op")
+}
+
+func TestNextIDForShard(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	for shard := 0; shard < 16; shard++ {
+		id, err := s.NextIDForShard(shard, 16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ShardForID(id, 16); got != shard {
+			t.Errorf("id %d in shard %d, want %d", id, got, shard)
+		}
+	}
+
+	if _, err = s.NextIDForShard(16, 16); err == nil {
+		t.Error("expected error for shard out of range")
+	}
+	if ShardForID(-1, 10) != int(uint64(1<<64-1)%10) {
+		t.Error("negative ids should use unsigned modulo")
+	}
+	if ShardForID(1, 0) != -1 {
+		t.Error("expected -1 for zero shards")
+	}
+
+	// 非自增时最低位是 workerID，2 的幂个分片中只有 workerID % 16 能得到
+	s, err = NewSnowflake(WithNonIncrement(), WithWorkID(func() (int64, error) { return 5, nil }))
+	if err != nil {
+		panic(err)
+	}
+	if _, err = s.NextIDForShard(3, 16); err == nil {
+		t.Error("expected error for unreachable shard")
+	}
+	if id, err := s.NextIDForShard(5, 16); err != nil || ShardForID(id, 16) != 5 {
+		t.Errorf("shard 5 of 16: id %d, err %v", id, err)
+	}
+	// 奇数个分片不受 workerID 限制
+	for shard := 0; shard < 3; shard++ {
+		if id, err := s.NextIDForShard(shard, 3); err != nil || ShardForID(id, 3) != shard {
+			t.Errorf("shard %d of 3: id %d, err %v", shard, id, err)
+		}
+	}
+}
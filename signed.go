@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// NextSignedID 生成一个 id，同时返回 HMAC-SHA256(hmacKey, 大端序的 id) 的前 4 个字节作为签名
+// 用于防篡改日志中的审计 id，不需要昂贵的非对称签名
+// 注意这只是完整性保护，不是安全意义上的签名：4 字节的签名可以被暴力伪造，只能防止意外的损坏
+func (s *Snowflake) NextSignedID(hmacKey []byte) (id int64, sig [4]byte, err error) {
+	if id, err = s.NextID(); err != nil {
+		return 0, sig, err
+	}
+	return id, signID(id, hmacKey), nil
+}
+
+// VerifySignedID 校验 NextSignedID 返回的签名
+func VerifySignedID(id int64, sig [4]byte, hmacKey []byte) bool {
+	want := signID(id, hmacKey)
+	return hmac.Equal(sig[:], want[:])
+}
+
+// signID 计算 id 的签名
+func signID(id int64, key []byte) (sig [4]byte) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(id))
+
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(b[:])
+	copy(sig[:], mac.Sum(nil))
+
+	return sig
+}
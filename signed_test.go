@@ -0,0 +1,26 @@
+package snowflake
+
+import "testing"
+
+func TestNextSignedID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	key := []byte("secret")
+	id, sig, err := s.NextSignedID(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !VerifySignedID(id, sig, key) {
+		t.Error("signature should verify")
+	}
+	if VerifySignedID(id+1, sig, key) {
+		t.Error("signature should not verify for another id")
+	}
+	if VerifySignedID(id, sig, []byte("other")) {
+		t.Error("signature should not verify with another key")
+	}
+}
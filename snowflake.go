@@ -1,10 +1,13 @@
 package snowflake
 
 import (
+	"crypto/rand"
+	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -49,12 +52,26 @@ const (
 
 	// 支持的最大序列 id 数量
 	sequenceMask = int64(-1 ^ (-1 << bitLenSequence))
+
+	// 时间回拨时默认的等待时间
+	defaultClockBackwardTimeout = time.Second
 )
 
+// ErrTimeBackward 时间回拨，并且等待后时间仍然没有恢复
+var ErrTimeBackward = errors.New("snowflake: time moved backwards")
+
 // WorkerID 生成 workID 的函数
 type WorkerID func() (int64, error)
 
+// Clock 获取当前时间的函数，返回 Unix 毫秒时间戳
+type Clock func() int64
+
 var (
+	// defaultClock 使用系统时间
+	defaultClock Clock = func() int64 {
+		return time.Now().UnixNano() / 1e6
+	}
+
 	// defaultWorkerID IPv4 直接用 ip 进行简单运算得到 workerID
 	defaultWorkerID WorkerID = func() (int64, error) {
 		addr, err := net.InterfaceAddrs()
@@ -82,6 +99,16 @@ type Snowflake struct {
 
 	// 生成 workID 的函数
 	w WorkerID
+	// 获取当前时间的函数
+	clock Clock
+	// 需要等待时钟的方法的最长等待时间
+	maxWait time.Duration
+	// 需要重试的方法的最大重试次数
+	maxRetries int
+	// 时间回拨时的等待时间
+	clockBackwardTimeout time.Duration
+	// 日志，为 nil 时不打印
+	logger Logger
 
 	// 非自增，换句话说，就是乱序，而默认为 false，则说明是自增
 	// 如果设置了，则会更换 workerID 和 sequenceID 的位置
@@ -100,6 +127,8 @@ type Snowflake struct {
 	sequenceMask int64
 
 	// id 快照
+	// 上一次生成的 id
+	lastID int64
 	// 上一次的时间
 	lastTime int64
 	// 时间部分
@@ -108,6 +137,45 @@ type Snowflake struct {
 	workerID int64
 	// 序列号部分
 	sequenceID int64
+	// 这一毫秒序列号的起始值，序列号自增回到这个值时说明这一毫秒的序列号用完了
+	sequenceStart int64
+	// 每一毫秒序列号的起始偏移量，用于主备节点使用相同 workerID 时错开序列号
+	sequenceOffset int64
+
+	// 熵源，设置后每一毫秒的序列号从随机值开始
+	entropy io.Reader
+	// 随机序列号，设置后每次生成都使用 crypto/rand 生成的随机序列号
+	randomSequence bool
+	// 倒序，设置后时间部分取反，序列号自减，越新的 id 越小
+	descending bool
+	// 类型标签，放在序列号的高 tagBits 位
+	tag int64
+	// 类型标签的 bit 长度
+	tagBits int64
+	// 当前 id 的优先级，放在类型标签之下的 priorityBits 位
+	priority int64
+	// 优先级的 bit 长度
+	priorityBits int64
+	// 校验和的 bit 长度，放在序列号的最低位
+	checksumBits int64
+	// 区域 id，放在 workerID 部分的高 regionBits 位
+	region int64
+	// 区域 id 的 bit 长度
+	regionBits int64
+	// 分组标签的 bit 长度，放在 workerID 部分的高位
+	groupBits int64
+	// 实体类型和实体 id 的 bit 长度，两者组成 NextIDForEntity 的 workerID 部分
+	entityKindBits int64
+	entityIDBits   int64
+
+	// NextRateLimitToken 各时间窗口的计数
+	rateCounts map[rateWindow]int64
+
+	// 是否已经关闭
+	closed bool
+
+	// NextID 耗时的直方图，保存 *latencyHistogram
+	latency atomic.Value
 }
 
 // Option 可选配置
@@ -127,6 +195,21 @@ func WithWorkID(w WorkerID) Option {
 	}
 }
 
+// WithClock 自定义时钟，一般用于测试
+func WithClock(c Clock) Option {
+	return func(s *Snowflake) {
+		s.clock = c
+	}
+}
+
+// WithClockBackwardTimeout 自定义时间回拨时的等待时间，默认 1s
+// 等待之后时间仍然比上一次慢，则返回 ErrTimeBackward
+func WithClockBackwardTimeout(d time.Duration) Option {
+	return func(s *Snowflake) {
+		s.clockBackwardTimeout = d
+	}
+}
+
 // WithNonIncrement 自定义非自增
 func WithNonIncrement() Option {
 	return func(s *Snowflake) {
@@ -134,6 +217,58 @@ func WithNonIncrement() Option {
 	}
 }
 
+// WithEntropySource 设置熵源，一般为 crypto/rand.Reader
+// 默认每一毫秒的序列号都从 0 开始，导致 id 容易被猜到，设置熵源后
+// 每进入新的一毫秒，会从 r 读取 bitLenSequence/8+1 个字节作为序列号的起始值
+// 时间上的顺序不变，但同一毫秒内的顺序变得不可预测
+// 读取失败时 NextID 返回错误
+func WithEntropySource(r io.Reader) Option {
+	return func(s *Snowflake) {
+		s.entropy = r
+	}
+}
+
+// WithRandomSequence 使用随机序列号代替自增的序列号
+// 适用于无法协调序列号的场景（离线设备、边缘节点等），每次生成都从 crypto/rand 读取一个随机的序列号
+// 时间部分仍然保证大致的顺序，但同一毫秒内可能重复，根据生日问题，同一个 worker 在同一毫秒内生成 k 个 id 时，
+// 碰撞概率约为 k(k-1)/2^(bitLenSequence+1)，比如 10 位序列号，每毫秒 1 个 id 时不会碰撞，每毫秒 2 个 id 时约为 0.1%
+func WithRandomSequence() Option {
+	return func(s *Snowflake) {
+		s.randomSequence = true
+	}
+}
+
+// WithDescendingSequence 自定义倒序，用于需要最新的数据排在最前面的场景（比如时间线）
+// 时间部分取反为 (1<<bitLenTime - 1) - (now - epoch)，序列号从最大值开始自减，
+// 因此按无符号整数升序排列时，越新的 id 越靠前，ORDER BY id ASC 就能得到从新到旧的结果
+// 注意这打破了默认的自增特性，依赖时间部分解析 id 的方法（比如 PartitionKey）得到的也是取反的时间
+func WithDescendingSequence() Option {
+	return func(s *Snowflake) {
+		s.descending = true
+	}
+}
+
+// WithTypeTag 自定义类型标签，把 tag 放到序列号的高 tagBits 位
+// 同一个实例为多种实体生成 id 时，不同实体的 id 在同一毫秒、同一序列号下也不会相同，
+// 一般为每种实体创建一个带不同标签的实例，代价是每毫秒可用的序列号减少为 1/2^tagBits
+// 要求 tag < 1<<tagBits，tagBits < bitLenSequence，否则 NewSnowflake 返回错误
+func WithTypeTag(tag, tagBits int64) Option {
+	return func(s *Snowflake) {
+		s.tag = tag
+		s.tagBits = tagBits
+	}
+}
+
+// WithReplicaSequenceOffset 自定义每一毫秒序列号的起始值，默认为 0
+// 主备切换期间两个节点可能使用相同的 workerID 同时生成 id，主节点使用 0，备节点使用 sequenceMask/2+1，
+// 每毫秒生成的 id 不超过一半容量时，两个节点的 id 不会重复
+// 要求 0 <= offset <= sequenceMask，否则 NewSnowflake 返回错误
+func WithReplicaSequenceOffset(offset int64) Option {
+	return func(s *Snowflake) {
+		s.sequenceOffset = offset
+	}
+}
+
 // WithLen 自定义各部分长度
 func WithLen(tl, wl, sl int64) Option {
 	return func(s *Snowflake) {
@@ -146,19 +281,28 @@ func WithLen(tl, wl, sl int64) Option {
 
 // NewSnowflake 新建一个雪花算法
 func NewSnowflake(opts ...Option) (*Snowflake, error) {
-	// 默认配置
+	// 默认配置，epoch 和 id 结构使用全局配置
+	layout := globalLayout()
 	s := &Snowflake{
-		lastTime:       epoch,
-		w:              defaultWorkerID,
-		bitLenTime:     bitLenTime,
-		bitLenWorkerID: bitLenWorkerID,
-		bitLenSequence: bitLenSequence,
-		sequenceMask:   sequenceMask,
-		sequenceID:     0,
-		nonIncrement:   false,
+		lastTime:             epoch,
+		w:                    defaultWorkerID,
+		clock:                defaultClock,
+		maxWait:              defaultMaxWait,
+		maxRetries:           defaultMaxRetries,
+		clockBackwardTimeout: defaultClockBackwardTimeout,
+		epoch:                GlobalEpoch(),
+		bitLenTime:           layout.Time,
+		bitLenWorkerID:       layout.WorkerID,
+		bitLenSequence:       layout.Sequence,
+		sequenceMask:         int64(-1 ^ (-1 << layout.Sequence)),
+		sequenceID:           0,
+		nonIncrement:         layout.NonIncrement,
 	}
 
-	// 初始化自定义配置
+	// 先应用全局默认配置，再初始化自定义配置
+	for _, opt := range registeredOptions() {
+		opt(s)
+	}
 	for i := range opts {
 		opts[i](s)
 	}
@@ -169,47 +313,139 @@ func NewSnowflake(opts ...Option) (*Snowflake, error) {
 		return nil, err
 	}
 	s.workerID = wid
+	if s.regionBits > 0 && s.regionBits < s.bitLenWorkerID {
+		s.workerID |= s.region << (s.bitLenWorkerID - s.regionBits)
+	}
+
+	// 检查配置
+	for _, i := range s.check() {
+		if i.fatal {
+			return nil, errors.New("snowflake: " + i.msg)
+		}
+	}
 
 	return s, nil
 }
 
-func (s *Snowflake) NextID() (id int64) {
+// NextID 生成下一个 id
+func (s *Snowflake) NextID() (int64, error) {
+	if h := s.latencyHistogram(); h != nil {
+		start := time.Now()
+		defer func() {
+			h.record(time.Since(start))
+		}()
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.nextID()
+}
+
+// NextIDWithTimestamp 生成一个 id，同时返回 id 中的时间，避免生成之后再调用 TimeFromID 解析
+func (s *Snowflake) NextIDWithTimestamp() (id int64, ts time.Time, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if id, err = s.nextID(); err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return id, time.UnixMilli(s.time + s.epoch), nil
+}
+
+// NextIDWithClock 生成一个 id，同时返回生成时使用的时钟值 lastTime（Unix 毫秒时间戳），用于审计日志事后检查时间回拨
+// 和 id 的时间部分不同，它是生成器内部记录的时钟值，不受 epoch、倒序等配置影响
+func (s *Snowflake) NextIDWithClock() (id int64, clockMs int64, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if id, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+
+	return id, s.lastTime, nil
+}
+
+// NextIDForTimezone 生成一个 id，同时返回 loc 时区的生成时间，用于需要按司法管辖区记录本地时间的审计系统
+// 等价于 NextIDWithTimestamp 之后调用 ts.In(loc)，loc 为 nil 时返回错误
+func (s *Snowflake) NextIDForTimezone(loc *time.Location) (id int64, localTime time.Time, err error) {
+	if loc == nil {
+		return 0, time.Time{}, errors.New("snowflake: nil location")
+	}
+
+	id, ts, err := s.NextIDWithTimestamp()
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+
+	return id, ts.In(loc), nil
+}
+
+// NextIDWithFields 生成一个 id，同时返回生成时的时间、workerID、序列号，用于结构化日志等场景
+// 直接读取生成时的状态，不需要再拆分 id：timeField 为距离 epoch 的毫秒数（设置了倒序时也不取反），
+// sequenceIDField 为计数部分，不包含类型标签、优先级和校验和
+func (s *Snowflake) NextIDWithFields() (id, timeField, workerIDField, sequenceIDField int64, err error) {
 	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if id, err = s.nextID(); err != nil {
+		return 0, 0, 0, 0, err
+	}
+
+	return id, s.time, s.workerID, s.sequenceID, nil
+}
+
+// nextID 生成下一个 id，调用方需要持有锁
+func (s *Snowflake) nextID() (id int64, err error) {
+	if s.closed {
+		return 0, ErrClosed
+	}
 
 	// 获取当前时间
-	now := time.Now().UnixNano() / 1e6
+	now := s.clock()
+
+	// 如果当前时间比上一次时间慢，则说明时间出了问题（时间重拨），如果不处理，会导致 id 重复
+	// 这里的处理方式是先等待一段时间（默认一秒钟），再判断，如果还是慢则报错
+	if s.lastTime > now {
+		time.Sleep(s.clockBackwardTimeout)
+		now = s.clock()
+		if s.lastTime > now {
+			return 0, ErrTimeBackward
+		}
+	}
 
-	// 如果当前时间比上一次时间快
-	// 则更新时间并且序列号初始化为 0
-	if s.lastTime < now {
+	if s.randomSequence {
+		// 随机序列号，不需要维护自增的计数
+		seq, err := s.readSequence(rand.Reader)
+		if err != nil {
+			return 0, err
+		}
 		s.lastTime = now
-		s.sequenceID = 0
-	} else if s.lastTime > now {
-		// 如果当前时间比上一次时间慢，则说明时间出了问题（时间重拨），如果不处理，会导致 id 重复
-		// 这里的处理方式是先等待一秒钟，再判断
-		time.Sleep(time.Second)
-		// 下面处理一样的，如果时间一样则序号增加，大于则更新时间，小于则报错
-		now = time.Now().UnixNano() / 1e6
-		if s.lastTime < now {
-			s.lastTime = now
-			s.sequenceID = 0
-		} else if s.lastTime > now {
-			log.Println("time error")
-			return
-		} else {
-			s.sequenceID = (s.sequenceID + 1) & s.sequenceMask
-			if s.sequenceID == 0 {
-				now = time.Now().UnixNano() / 1e6
-			}
+		s.sequenceID = seq
+	} else if s.lastTime < now {
+		// 如果当前时间比上一次时间快
+		// 则更新时间并且初始化序列号
+		if err = s.resetSequence(now); err != nil {
+			return 0, err
 		}
 	} else {
-		// 如果时间相同，则序列号自增
+		// 如果时间相同，则序列号自增（设置了倒序则自减）
 		// 注意达到最大值后需要重新从 0 开始
-		s.sequenceID = (s.sequenceID + 1) & s.sequenceMask
+		if !s.descending {
+			s.sequenceID = (s.sequenceID + 1) & s.counterMask()
+		} else {
+			s.sequenceID = (s.sequenceID - 1) & s.counterMask()
+		}
 
-		// 如果序列号变成 0，则说明序列号使用完了，所以需要更新时间，然后重新开始计算
-		if s.sequenceID == 0 {
-			now = time.Now().UnixNano() / 1e6
+		// 如果序列号回到了这一毫秒的起始值，则说明序列号使用完了，所以需要等到下一毫秒，然后重新开始计算
+		if s.sequenceID == s.sequenceStart {
+			for now <= s.lastTime {
+				now = s.clock()
+			}
+			if err = s.resetSequence(now); err != nil {
+				return 0, err
+			}
 		}
 	}
 
@@ -217,22 +453,67 @@ func (s *Snowflake) NextID() (id int64) {
 	s.time = now - s.epoch
 
 	// 通过位运算生成结果
-	// 结构为：
-	//        time--work--sequence
-	// 如果设置了 nonIncrement=true，则为
-	//        time--sequence--work
-	if !s.NonIncrementing() {
-		id = s.time<<(s.bitLenWorkerID+s.bitLenSequence) | s.workerID<<s.bitLenWorkerID | s.sequenceID
+	// 如果设置了倒序，则时间部分取反，使得越新的 id 越小
+	if !s.descending {
+		id = s.compose(s.time, s.workerID, s.sequenceField())
 	} else {
-		id = s.time<<(s.bitLenWorkerID+s.bitLenSequence) | s.sequenceID<<s.bitLenSequence | s.workerID
+		id = s.compose(1<<s.bitLenTime-1-s.time, s.workerID, s.sequenceField())
 	}
+	id = s.withChecksum(id)
 
-	s.mutex.Unlock()
+	s.lastID = id
 
 	//fmt.Println()
 	//fmt.Println(s.time, s.sequenceID, s.workerID)
 
-	return
+	return id, nil
+}
+
+// resetSequence 进入新的一毫秒，更新时间并初始化序列号
+// 默认从 WithReplicaSequenceOffset 设置的偏移量开始（设置了倒序则从最大值减去偏移量开始），如果设置了熵源，则从熵源读取一个随机的起始值
+func (s *Snowflake) resetSequence(now int64) error {
+	seq := s.sequenceOffset
+
+	if s.descending {
+		seq = s.counterMask() - s.sequenceOffset
+	}
+	if s.entropy != nil {
+		var err error
+		if seq, err = s.readSequence(s.entropy); err != nil {
+			return err
+		}
+	}
+
+	s.lastTime = now
+	s.sequenceID = seq
+	s.sequenceStart = seq
+
+	return nil
+}
+
+// readSequence 从 r 读取 bitLenSequence/8+1 个字节，截取为一个序列号
+func (s *Snowflake) readSequence(r io.Reader) (int64, error) {
+	var seq int64
+
+	b := make([]byte, s.bitLenSequence/8+1)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return 0, fmt.Errorf("snowflake: read entropy: %w", err)
+	}
+	for _, c := range b {
+		seq = seq<<8 | int64(c)
+	}
+
+	return seq & s.counterMask(), nil
+}
+
+// counterMask 序列号中计数部分的掩码，默认为整个序列号，设置了类型标签、优先级、校验和时要去掉它们占用的位
+func (s *Snowflake) counterMask() int64 {
+	return s.sequenceMask >> (s.tagBits + s.priorityBits + s.checksumBits)
+}
+
+// sequenceField 当前 id 的序列号部分，从高到低依次为类型标签、优先级、计数和校验和，校验和的位置为 0
+func (s *Snowflake) sequenceField() int64 {
+	return s.tag<<(s.bitLenSequence-s.tagBits) | s.priority<<(s.bitLenSequence-s.tagBits-s.priorityBits) | s.sequenceID<<s.checksumBits
 }
 
 func (s *Snowflake) Time() int64 {
@@ -271,6 +552,14 @@ func (s *Snowflake) SequenceMask() int64 {
 	return s.sequenceMask
 }
 
+// LastID 返回上一次生成的 id，还没有生成过时返回 0
+func (s *Snowflake) LastID() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.lastID
+}
+
 func (s *Snowflake) LastTime() int64 {
 	return s.lastTime
 }
@@ -308,14 +597,17 @@ func (s *Snowflake) String() string {
 }
 
 // Parse 解析生成的 id 为各个部分
-// 使用默认各个部分长度，默认自增分配
+// 使用默认各个部分长度，默认自增分配，time 为时间部分，即距离 Unix 时间的毫秒数
+//
+// Deprecated: 只适用于默认结构，使用 WithLen、WithNonIncrement、WithEpoch 时结果不正确，请使用 (*Snowflake).Parse
 func Parse(id uint64) (time, workerID, sequenceID uint64) {
-	const maskSequence = uint64((1<<bitLenSequence - 1) << bitLenWorkerID)
-	const maskMachineID = uint64(1<<bitLenWorkerID - 1)
-
-	time = id >> (bitLenSequence + bitLenWorkerID)
-	workerID = id & maskSequence >> bitLenWorkerID
-	sequenceID = id & maskMachineID
+	t, w, seq := DefaultBitLayout.Decompose(int64(id))
+	return uint64(t), uint64(w), uint64(seq)
+}
 
-	return
+// Parse 按照本实例的结构解析 id 为各个部分，timestamp 为 Unix 毫秒时间戳（已经加上 epoch）
+// 支持 WithLen 自定义的长度，以及 WithNonIncrement、WithDescendingSequence
+func (s *Snowflake) Parse(id uint64) (timestamp, workerID, sequenceID uint64) {
+	_, w, seq := s.decompose(int64(id))
+	return uint64(s.DecodeTimestamp(int64(id))), uint64(w), uint64(seq)
 }
@@ -1,6 +1,7 @@
 package snowflake
 
 import (
+	"bytes"
 	"fmt"
 	"testing"
 	"time"
@@ -14,14 +15,23 @@ func get(a int64) {
 	fmt.Println()
 }
 
+func next(t testing.TB, s *Snowflake) int64 {
+	t.Helper()
+	id, err := s.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
 func TestName(t *testing.T) {
 	s, _ := NewSnowflake()
-	get(s.NextID())
-	get(s.NextID())
-	get(s.NextID())
+	get(next(t, s))
+	get(next(t, s))
+	get(next(t, s))
 	time.Sleep(100 * time.Millisecond)
-	get(s.NextID())
-	get(s.NextID())
+	get(next(t, s))
+	get(next(t, s))
 }
 
 func TestNew(t *testing.T) {
@@ -29,12 +39,12 @@ func TestNew(t *testing.T) {
 	if err != nil {
 		panic(err)
 	}
-	fmt.Println(s.NextID())
-	fmt.Println(s.NextID())
-	fmt.Println(s.NextID())
+	fmt.Println(next(t, s))
+	fmt.Println(next(t, s))
+	fmt.Println(next(t, s))
 	time.Sleep(time.Second)
-	fmt.Println(s.NextID())
-	fmt.Println(s.NextID())
+	fmt.Println(next(t, s))
+	fmt.Println(next(t, s))
 }
 
 func TestParse(t *testing.T) {
@@ -43,14 +53,14 @@ func TestParse(t *testing.T) {
 		panic(err)
 	}
 
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
 	time.Sleep(time.Second)
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
 }
 
 func TestWithWorkID(t *testing.T) {
@@ -65,9 +75,9 @@ func TestWithWorkID(t *testing.T) {
 		panic(err)
 	}
 
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
-	fmt.Println(Parse(uint64(s.NextID())))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
+	fmt.Println(Parse(uint64(next(t, s))))
 }
 
 func TestNonIncrement(t *testing.T) {
@@ -76,8 +86,263 @@ func TestNonIncrement(t *testing.T) {
 		panic(err)
 	}
 
-	fmt.Println(s.NextID())
-	fmt.Println(s.NextID())
-	fmt.Println(s.NextID())
+	fmt.Println(next(t, s))
+	fmt.Println(next(t, s))
+	fmt.Println(next(t, s))
+
+}
+
+func TestWithEntropySource(t *testing.T) {
+	s, err := NewSnowflake(WithEntropySource(bytes.NewReader([]byte{0x12, 0x34})))
+	if err != nil {
+		panic(err)
+	}
+
+	next(t, s)
+	if s.SequenceID() != 0x1234&s.SequenceMask() {
+		t.Errorf("sequence = %d, want %d", s.SequenceID(), 0x1234&s.SequenceMask())
+	}
+
+	// 熵源读完之后，进入下一毫秒时应该返回错误
+	time.Sleep(2 * time.Millisecond)
+	if _, err = s.NextID(); err == nil {
+		t.Error("expected error from exhausted entropy source")
+	}
+}
+
+func TestSequenceExhausted(t *testing.T) {
+	s, err := NewSnowflake(WithLen(41, 20, 2))
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		id := next(t, s)
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestWithRandomSequence(t *testing.T) {
+	s, err := NewSnowflake(WithRandomSequence())
+	if err != nil {
+		panic(err)
+	}
 
+	for i := 0; i < 10; i++ {
+		next(t, s)
+		if s.SequenceID() < 0 || s.SequenceID() > s.SequenceMask() {
+			t.Fatalf("sequence %d out of range", s.SequenceID())
+		}
+	}
+}
+
+func TestWithDescendingSequence(t *testing.T) {
+	s, err := NewSnowflake(WithDescendingSequence())
+	if err != nil {
+		panic(err)
+	}
+
+	prev := next(t, s)
+	for i := 0; i < 2000; i++ {
+		id := next(t, s)
+		if id >= prev {
+			t.Fatalf("id %d not less than %d", id, prev)
+		}
+		prev = id
+	}
+}
+
+func TestWithTypeTag(t *testing.T) {
+	users, err := NewSnowflake(WithTypeTag(1, 2))
+	if err != nil {
+		panic(err)
+	}
+	orders, err := NewSnowflake(WithTypeTag(2, 2))
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 1000; i++ {
+		u, o := next(t, users), next(t, orders)
+		if TagFromID(u, users.Layout(), 2) != 1 || TagFromID(o, orders.Layout(), 2) != 2 {
+			t.Fatalf("wrong tags for %d and %d", u, o)
+		}
+		if seen[u] || seen[o] {
+			t.Fatal("duplicate id")
+		}
+		seen[u], seen[o] = true, true
+	}
+
+	if _, err = NewSnowflake(WithTypeTag(4, 2)); err == nil {
+		t.Error("expected error for tag too large")
+	}
+	if _, err = NewSnowflake(WithTypeTag(0, bitLenSequence)); err == nil {
+		t.Error("expected error for too many tag bits")
+	}
+}
+
+func TestWithReplicaSequenceOffset(t *testing.T) {
+	now := time.Now().UnixMilli()
+	clock := func() int64 { return now }
+
+	primary, err := NewSnowflake(WithClock(clock))
+	if err != nil {
+		panic(err)
+	}
+	replica, err := NewSnowflake(WithClock(clock), WithReplicaSequenceOffset(sequenceMask/2+1))
+	if err != nil {
+		panic(err)
+	}
+
+	// 相同的 workerID、相同的毫秒，各自生成不超过一半容量的 id 时不会重复
+	seen := make(map[int64]bool)
+	for i := 0; i < int(sequenceMask/2); i++ {
+		for _, s := range []*Snowflake{primary, replica} {
+			id := next(t, s)
+			if seen[id] {
+				t.Fatalf("duplicate id %d", id)
+			}
+			seen[id] = true
+		}
+	}
+	if want := sequenceMask/2 + 1 + sequenceMask/2 - 1; replica.SequenceID() != want {
+		t.Errorf("replica sequence = %d, want %d", replica.SequenceID(), want)
+	}
+
+	for _, offset := range []int64{-1, sequenceMask + 1} {
+		if _, err = NewSnowflake(WithReplicaSequenceOffset(offset)); err == nil {
+			t.Errorf("offset %d: expected error", offset)
+		}
+	}
+}
+
+func TestNextIDWithTimestamp(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		id, ts, err := s.NextIDWithTimestamp()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ts.Equal(s.TimeFromID(id)) {
+			t.Errorf("timestamp %s, want %s", ts, s.TimeFromID(id))
+		}
+	}
+}
+
+func TestNextIDWithFields(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	for i := 0; i < 100; i++ {
+		id, tm, w, seq, err := s.NextIDWithFields()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		dt, dw, dseq := s.decompose(id)
+		if tm != dt || w != dw || seq != dseq {
+			t.Fatalf("fields (%d, %d, %d), want (%d, %d, %d)", tm, w, seq, dt, dw, dseq)
+		}
+	}
+}
+
+func TestNextIDForTimezone(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	loc := time.FixedZone("UTC+8", 8*60*60)
+	id, local, err := s.NextIDForTimezone(loc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if local.Location() != loc {
+		t.Errorf("location = %s, want %s", local.Location(), loc)
+	}
+	if !local.Equal(s.TimeFromID(id)) {
+		t.Errorf("time = %s, want %s", local, s.TimeFromID(id))
+	}
+
+	if _, _, err = s.NextIDForTimezone(nil); err == nil {
+		t.Error("expected error for nil location")
+	}
+}
+
+func TestNextIDWithClock(t *testing.T) {
+	now := time.Now().UnixMilli()
+	s, err := NewSnowflake(WithClock(func() int64 { return now }), WithDescendingSequence())
+	if err != nil {
+		panic(err)
+	}
+
+	id, clock, err := s.NextIDWithClock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if clock != now {
+		t.Errorf("clock = %d, want %d", clock, now)
+	}
+	if s.DecodeTimestamp(id) != now {
+		t.Errorf("id time = %d, want %d", s.DecodeTimestamp(id), now)
+	}
+}
+
+func TestSnowflakeParse(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UnixMilli()
+	worker := WithWorkID(func() (int64, error) { return 3, nil })
+
+	cases := []struct {
+		name string
+		opts []Option
+	}{
+		{"default", []Option{worker}},
+		{"custom len", []Option{worker, WithLen(39, 16, 8), WithEpoch(recent)}},
+		{"non increment", []Option{worker, WithNonIncrement()}},
+		{"descending", []Option{worker, WithDescendingSequence(), WithEpoch(recent)}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSnowflake(c.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 10; i++ {
+				id, clock, err := s.NextIDWithClock()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				ts, w, seq := s.Parse(uint64(id))
+				if ts != uint64(clock) || w != 3 || seq != uint64(s.sequenceField()) {
+					t.Fatalf("id %d parsed as (%d, %d, %d), want (%d, 3, %d)", id, ts, w, seq, clock, s.sequenceField())
+				}
+			}
+		})
+	}
+
+	// 默认结构下包级别的 Parse 和实例的 Parse 结果相同
+	s, err := NewSnowflake(worker)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uint64(next(t, s))
+	gt, gw, gseq := Parse(id)
+	wt, ww, wseq := s.Parse(id)
+	if gt != wt || gw != ww || gseq != wseq {
+		t.Errorf("Parse = (%d, %d, %d), want (%d, %d, %d)", gt, gw, gseq, wt, ww, wseq)
+	}
 }
@@ -0,0 +1,12 @@
+// Package snowflakeproto 雪花算法 id 的 protobuf 定义，用于 gRPC 接口中传递有类型的 id
+//
+// 使用 (*snowflake.Snowflake).NextIDAsProto 生成消息，snowflake.FromProto 取出 id
+//
+// 消息定义在 proto/snowflake.proto 中，Go 代码生成到 snowflakepb 包：
+//
+//	go generate ./snowflakeproto
+//
+// 生成的代码已经提交，只有修改 .proto 后才需要安装 protoc 和 protoc-gen-go 重新生成
+package snowflakeproto
+
+//go:generate protoc --go_out=. --go_opt=module=github.com/edte/snowflake/snowflakeproto proto/snowflake.proto
@@ -0,0 +1,155 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: proto/snowflake.proto
+
+package snowflakepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// SnowflakeID 雪花算法 id，同时带上拆分后的各部分，避免 gRPC 接口直接使用 int64 丢失类型信息
+type SnowflakeID struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 完整的 id
+	Value int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	// 生成时间，Unix 毫秒时间戳
+	TimeMs int64 `protobuf:"varint,2,opt,name=time_ms,json=timeMs,proto3" json:"time_ms,omitempty"`
+	// workerID 部分
+	WorkerId int64 `protobuf:"varint,3,opt,name=worker_id,json=workerId,proto3" json:"worker_id,omitempty"`
+	// 序列号部分
+	SequenceId    int64 `protobuf:"varint,4,opt,name=sequence_id,json=sequenceId,proto3" json:"sequence_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnowflakeID) Reset() {
+	*x = SnowflakeID{}
+	mi := &file_proto_snowflake_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnowflakeID) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnowflakeID) ProtoMessage() {}
+
+func (x *SnowflakeID) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_snowflake_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnowflakeID.ProtoReflect.Descriptor instead.
+func (*SnowflakeID) Descriptor() ([]byte, []int) {
+	return file_proto_snowflake_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SnowflakeID) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *SnowflakeID) GetTimeMs() int64 {
+	if x != nil {
+		return x.TimeMs
+	}
+	return 0
+}
+
+func (x *SnowflakeID) GetWorkerId() int64 {
+	if x != nil {
+		return x.WorkerId
+	}
+	return 0
+}
+
+func (x *SnowflakeID) GetSequenceId() int64 {
+	if x != nil {
+		return x.SequenceId
+	}
+	return 0
+}
+
+var File_proto_snowflake_proto protoreflect.FileDescriptor
+
+const file_proto_snowflake_proto_rawDesc = "" +
+	"\n" +
+	"\x15proto/snowflake.proto\x12\tsnowflake\"z\n" +
+	"\vSnowflakeID\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\x12\x17\n" +
+	"\atime_ms\x18\x02 \x01(\x03R\x06timeMs\x12\x1b\n" +
+	"\tworker_id\x18\x03 \x01(\x03R\bworkerId\x12\x1f\n" +
+	"\vsequence_id\x18\x04 \x01(\x03R\n" +
+	"sequenceIdB6Z4github.com/edte/snowflake/snowflakeproto/snowflakepbb\x06proto3"
+
+var (
+	file_proto_snowflake_proto_rawDescOnce sync.Once
+	file_proto_snowflake_proto_rawDescData []byte
+)
+
+func file_proto_snowflake_proto_rawDescGZIP() []byte {
+	file_proto_snowflake_proto_rawDescOnce.Do(func() {
+		file_proto_snowflake_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_snowflake_proto_rawDesc), len(file_proto_snowflake_proto_rawDesc)))
+	})
+	return file_proto_snowflake_proto_rawDescData
+}
+
+var file_proto_snowflake_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_proto_snowflake_proto_goTypes = []any{
+	(*SnowflakeID)(nil), // 0: snowflake.SnowflakeID
+}
+var file_proto_snowflake_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_proto_snowflake_proto_init() }
+func file_proto_snowflake_proto_init() {
+	if File_proto_snowflake_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_snowflake_proto_rawDesc), len(file_proto_snowflake_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_snowflake_proto_goTypes,
+		DependencyIndexes: file_proto_snowflake_proto_depIdxs,
+		MessageInfos:      file_proto_snowflake_proto_msgTypes,
+	}.Build()
+	File_proto_snowflake_proto = out.File
+	file_proto_snowflake_proto_goTypes = nil
+	file_proto_snowflake_proto_depIdxs = nil
+}
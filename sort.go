@@ -0,0 +1,48 @@
+package snowflake
+
+import (
+	"log"
+	"sort"
+)
+
+// SortedDeduplicate 按无符号大小排序并去除重复的 id，时间复杂度 O(n log n)
+// 排序在 ids 上原地进行，返回去重后的切片，与 ids 共用底层数组
+// 正常情况下雪花算法生成的 id 不会重复，这里只是防御性的检查，如果发现重复则说明有 bug，会打印警告
+func SortedDeduplicate(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool {
+		return uint64(ids[i]) < uint64(ids[j])
+	})
+
+	if len(ids) == 0 {
+		return ids
+	}
+
+	n := 1
+	for i := 1; i < len(ids); i++ {
+		if ids[i] == ids[n-1] {
+			log.Printf("snowflake: duplicate id %d", ids[i])
+			continue
+		}
+		ids[n] = ids[i]
+		n++
+	}
+
+	return ids[:n]
+}
+
+// SortIDs 按无符号大小原地排序 id，时间复杂度 O(n log n)
+// sort.Slice 直接比较 int64 时，符号位被设置的 id 会排到最前面，这里使用无符号比较
+// 时间部分在最高位，因此两种模式下结果都按时间排序；设置了非自增时 workerID 和序列号的位置互换，
+// 同一毫秒内的顺序不再是生成顺序
+func (s *Snowflake) SortIDs(ids []int64) {
+	sort.Slice(ids, func(i, j int) bool {
+		return uint64(ids[i]) < uint64(ids[j])
+	})
+}
+
+// IsSorted 判断 id 是否已经按 SortIDs 的顺序排好
+func (s *Snowflake) IsSorted(ids []int64) bool {
+	return sort.SliceIsSorted(ids, func(i, j int) bool {
+		return uint64(ids[i]) < uint64(ids[j])
+	})
+}
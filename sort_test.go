@@ -0,0 +1,52 @@
+package snowflake
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortedDeduplicate(t *testing.T) {
+	got := SortedDeduplicate([]int64{3, -1, 1, 3, 2, 1})
+	want := []int64{1, 2, 3, -1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if got := SortedDeduplicate(nil); len(got) != 0 {
+		t.Errorf("got %v, want empty", got)
+	}
+}
+
+func TestSortIDs(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithNonIncrement()}} {
+		s, err := NewSnowflake(opts...)
+		if err != nil {
+			panic(err)
+		}
+
+		var ids []int64
+		for i := 0; i < 100; i++ {
+			ids = append(ids, next(t, s))
+		}
+		ids = append(ids, -1, 1)
+
+		s.SortIDs(ids)
+		if !s.IsSorted(ids) {
+			t.Fatal("ids should be sorted")
+		}
+		if ids[0] != 1 || ids[len(ids)-1] != -1 {
+			t.Errorf("ids should be sorted as unsigned, got first %d last %d", ids[0], ids[len(ids)-1])
+		}
+
+		for i := 1; i < len(ids)-1; i++ {
+			if tp, _, _ := s.decompose(ids[i-1]); tp > s.TimeFromID(ids[i]).UnixMilli()-s.epoch {
+				t.Fatalf("id %d sorted before an older id", ids[i-1])
+			}
+		}
+
+		ids[1], ids[2] = ids[2], ids[1]
+		if s.IsSorted(ids) {
+			t.Error("swapped ids should not be sorted")
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package snowflake
+
+import (
+	"math/bits"
+	"time"
+)
+
+// taskHintBits 任务耗时提示占用序列号的高位数
+const taskHintBits = 4
+
+// NextTaskID 生成一个带预计耗时提示的任务 id，调度系统不需要读取任务的元数据就能知道大概的耗时
+// 提示为 min(floor(log2(estimatedDurationMs)), 15)，放在序列号的高 4 位，
+// 因此只有 16 个档位：1ms、2ms、4ms ... 32768ms 及以上，每一档是上一档的两倍
+// 每毫秒可用的序列号减少为 1/16，并且不能和普通的 NextID 混用同一个实例
+func (s *Snowflake) NextTaskID(estimatedDurationMs int64) (int64, error) {
+	var hint int64
+	if estimatedDurationMs > 1 {
+		hint = int64(bits.Len64(uint64(estimatedDurationMs))) - 1
+	}
+	if hint > 1<<taskHintBits-1 {
+		hint = 1<<taskHintBits - 1
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.nextIDEmbedding(taskHintBits, hint)
+}
+
+// TaskDurationHint 取出 NextTaskID 生成的 id 中的耗时提示，返回 2^hint 毫秒作为粗略的预计耗时
+func TaskDurationHint(id int64, layout BitLayout) time.Duration {
+	_, _, seq := layout.Decompose(id)
+	hint := seq >> (layout.Sequence - taskHintBits)
+	return time.Duration(int64(1)<<hint) * time.Millisecond
+}
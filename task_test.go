@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextTaskID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	cases := []struct {
+		ms   int64
+		want time.Duration
+	}{
+		{0, time.Millisecond},
+		{1, time.Millisecond},
+		{3, 2 * time.Millisecond},
+		{1000, 512 * time.Millisecond},
+		{1 << 20, 1 << 15 * time.Millisecond},
+	}
+
+	for _, c := range cases {
+		id, err := s.NextTaskID(c.ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := TaskDurationHint(id, s.Layout()); got != c.want {
+			t.Errorf("hint for %dms = %s, want %s", c.ms, got, c.want)
+		}
+	}
+
+	// 超过每毫秒 64 个之后需要等待下一毫秒，仍然不会重复
+	seen := make(map[int64]bool)
+	for i := 0; i < 500; i++ {
+		id, err := s.NextTaskID(100)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+	}
+}
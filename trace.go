@@ -0,0 +1,44 @@
+package snowflake
+
+import "encoding/binary"
+
+// NextCorrelationID 生成分布式追踪中的 span id，同时返回上一次生成的 id 作为父 id
+// 两者在同一次加锁中得到，id → parentID → ... 隐式地形成一条因果链，不需要额外的存储
+// 第一次调用时 parentID 为 0
+func (s *Snowflake) NextCorrelationID() (id, parentID int64, err error) {
+	return s.nextIDWithPrev()
+}
+
+// NextIDPair 在一次加锁中连续生成两个 id，分别作为请求 id 和响应的关联 id
+// 默认结构下，两个 id 在同一毫秒内时 correlationID = requestID + 1；
+// 如果第二个 id 跨过了毫秒边界（或者序列号用完），两者的时间部分不同，只保证 correlationID > requestID
+// 时间回拨时返回错误
+func (s *Snowflake) NextIDPair() (requestID, correlationID int64, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if requestID, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+	if correlationID, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+
+	return requestID, correlationID, nil
+}
+
+// NextRequestSpanID 生成 OpenTelemetry 的 span id 和 trace id
+// span id 为 id 的 8 字节大端序，trace id 为 workerID(8)--id(8)，
+// trace id 按时间排序并带有生成服务的 workerID，不需要额外的传播系统也能把 trace 和服务关联起来
+func (s *Snowflake) NextRequestSpanID() (spanID [8]byte, traceID [16]byte, err error) {
+	id, err := s.NextID()
+	if err != nil {
+		return spanID, traceID, err
+	}
+
+	binary.BigEndian.PutUint64(spanID[:], uint64(id))
+	binary.BigEndian.PutUint64(traceID[:8], uint64(s.WorkerFromID(id)))
+	copy(traceID[8:], spanID[:])
+
+	return spanID, traceID, nil
+}
@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestNextCorrelationID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	id, parent, err := s.NextCorrelationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent != 0 {
+		t.Errorf("first parent = %d, want 0", parent)
+	}
+
+	child, parent, err := s.NextCorrelationID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent != id || child <= id {
+		t.Errorf("got (%d, %d) after %d", child, parent, id)
+	}
+}
+
+func TestNextIDPair(t *testing.T) {
+	now := time.Now().UnixMilli()
+	s, err := NewSnowflake(WithClock(func() int64 { return now }))
+	if err != nil {
+		panic(err)
+	}
+
+	req, corr, err := s.NextIDPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if corr != req+1 {
+		t.Errorf("correlation id = %d, want %d", corr, req+1)
+	}
+}
+
+func TestNextRequestSpanID(t *testing.T) {
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 9, nil }))
+	if err != nil {
+		panic(err)
+	}
+
+	span, trace, err := s.NextRequestSpanID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id := int64(binary.BigEndian.Uint64(span[:])); id != s.LastID() {
+		t.Errorf("span id = %d, want %d", id, s.LastID())
+	}
+	if w := binary.BigEndian.Uint64(trace[:8]); w != 9 {
+		t.Errorf("trace worker = %d, want 9", w)
+	}
+	if !bytes.Equal(trace[8:], span[:]) {
+		t.Errorf("trace id %x should end with span id %x", trace, span)
+	}
+}
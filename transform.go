@@ -0,0 +1,20 @@
+package snowflake
+
+// ApplyTransformation 对 id 做一个确定性的变换后返回，用于保存之前的后处理，比如异或一个固定的 key 做简单混淆
+// fn 为 nil 时原样返回；库不检查 fn 是否可逆，需要还原时由调用方自己提供逆变换
+func (s *Snowflake) ApplyTransformation(id int64, fn func(int64) int64) int64 {
+	if fn == nil {
+		return id
+	}
+	return fn(id)
+}
+
+// NextIDTransformed 生成一个 id，并立即用 fn 变换，见 ApplyTransformation
+// 注意变换后的 id 不一定保持唯一和有序，取决于 fn 是否是单调的双射
+func (s *Snowflake) NextIDTransformed(fn func(int64) int64) (int64, error) {
+	id, err := s.NextID()
+	if err != nil {
+		return 0, err
+	}
+	return s.ApplyTransformation(id, fn), nil
+}
@@ -0,0 +1,27 @@
+package snowflake
+
+import "testing"
+
+func TestNextIDTransformed(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	const key = 0x5bd1e995
+	xor := func(id int64) int64 { return id ^ key }
+
+	id, err := s.NextIDTransformed(xor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != s.LastID()^key {
+		t.Errorf("id = %d, want %d", id, s.LastID()^key)
+	}
+	if s.ApplyTransformation(id, xor) != s.LastID() {
+		t.Error("applying xor twice should restore the id")
+	}
+	if s.ApplyTransformation(id, nil) != id {
+		t.Error("nil transformation should return the id unchanged")
+	}
+}
@@ -0,0 +1,34 @@
+package snowflake
+
+import "fmt"
+
+// transitionBits 状态转换占用序列号的高位数，from 和 to 各占一半
+const transitionBits = 8
+
+// NextStateTransitionID 生成一个状态机事件 id，把 fromState<<4 | toState 放在序列号的高 8 位
+// 读取事件日志时不需要解析事件内容就能知道是哪一个状态转换，状态值必须小于 16
+// 每毫秒可用的序列号减少为 1/256，默认 10 位序列号时每毫秒最多 4 个，用完后等待下一毫秒，
+// 需要更多的话使用 WithLen 加长序列号；不能和普通的 NextID 混用同一个实例
+func (s *Snowflake) NextStateTransitionID(fromState, toState uint8) (int64, error) {
+	if fromState >= 1<<(transitionBits/2) || toState >= 1<<(transitionBits/2) {
+		return 0, fmt.Errorf("snowflake: state transition %d -> %d does not fit in %d bits", fromState, toState, transitionBits)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.nextIDEmbedding(transitionBits, int64(fromState)<<(transitionBits/2)|int64(toState))
+}
+
+// ParseStateTransition 取出序列号高 transitionBits 位中的状态转换，baseID 为清除这些位之后的 id
+func ParseStateTransition(id int64, layout BitLayout, transitionBits int) (fromState, toState uint8, baseID int64) {
+	t, w, seq := layout.Decompose(id)
+
+	shift := layout.Sequence - int64(transitionBits)
+	transition := seq >> shift
+	half := transitionBits / 2
+
+	fromState = uint8(transition >> half)
+	toState = uint8(transition & (1<<half - 1))
+	return fromState, toState, layout.Compose(t, w, seq&(1<<shift-1))
+}
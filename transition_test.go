@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextStateTransitionID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	seen := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		from, to := uint8(i%16), uint8((i+1)%16)
+
+		id, err := s.NextStateTransitionID(from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %d", id)
+		}
+		seen[id] = true
+
+		gotFrom, gotTo, base := ParseStateTransition(id, s.Layout(), transitionBits)
+		if gotFrom != from || gotTo != to {
+			t.Fatalf("transition = %d -> %d, want %d -> %d", gotFrom, gotTo, from, to)
+		}
+		if base != s.LastID() {
+			t.Fatalf("base id = %d, want %d", base, s.LastID())
+		}
+	}
+
+	if _, err = s.NextStateTransitionID(16, 0); err == nil {
+		t.Error("expected error for a state that does not fit")
+	}
+}
+
+func TestNextStateTransitionIDCapacity(t *testing.T) {
+	// 时钟每调用 50 次才前进 1ms，保证前几个 id 都在同一毫秒
+	base, calls := time.Now().UnixMilli(), int64(0)
+	s, err := NewSnowflake(WithClock(func() int64 {
+		calls++
+		return base + calls/50
+	}))
+	if err != nil {
+		panic(err)
+	}
+
+	perMs := make(map[int64]int)
+	for i := 0; i < 20; i++ {
+		id, err := s.NextStateTransitionID(1, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tm, _, _ := s.decompose(id)
+		perMs[tm]++
+	}
+
+	// 默认 10 位序列号，高 8 位用于状态转换，每毫秒只剩 4 个
+	for tm, n := range perMs {
+		if n > 4 {
+			t.Errorf("%d ids in millisecond %d, want at most 4", n, tm)
+		}
+	}
+	if n := perMs[base-s.Epoch()]; n != 4 {
+		t.Errorf("%d ids in the first millisecond, want 4", n)
+	}
+}
@@ -0,0 +1,51 @@
+package snowflake
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// TypedID 带实体类型的 id，T 一般为实体本身的类型，比如 TypedID[User]
+// 不同实体的 id 是不同的类型，把用户 id 传给需要订单 id 的函数会在编译期报错
+type TypedID[T any] int64
+
+// Int64 返回原始的 id
+func (id TypedID[T]) Int64() int64 {
+	return int64(id)
+}
+
+// MarshalJSON 编码为 JSON 字符串，避免 JavaScript 等语言丢失精度
+func (id TypedID[T]) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + strconv.FormatInt(int64(id), 10) + `"`), nil
+}
+
+// UnmarshalJSON 解码 JSON 字符串或数字
+func (id *TypedID[T]) UnmarshalJSON(b []byte) error {
+	n, err := strconv.ParseInt(string(bytes.Trim(b, `"`)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("snowflake: invalid id %s: %w", b, err)
+	}
+	*id = TypedID[T](n)
+	return nil
+}
+
+// TypedSnowflake 生成带实体类型的 id 的雪花算法
+type TypedSnowflake[T any] struct {
+	sf *Snowflake
+}
+
+// NewTyped 新建一个生成 TypedID[T] 的雪花算法，配置和 NewSnowflake 一样
+func NewTyped[T any](opts ...Option) (*TypedSnowflake[T], error) {
+	sf, err := NewSnowflake(opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &TypedSnowflake[T]{sf: sf}, nil
+}
+
+// NextID 生成下一个 id
+func (ts *TypedSnowflake[T]) NextID() (TypedID[T], error) {
+	id, err := ts.sf.NextID()
+	return TypedID[T](id), err
+}
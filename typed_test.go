@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type user struct{}
+
+func TestTypedSnowflake(t *testing.T) {
+	ts, err := NewTyped[user]()
+	if err != nil {
+		panic(err)
+	}
+
+	id, err := ts.NextID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got TypedID[user]
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != id || got.Int64() != int64(id) {
+		t.Errorf("got %d, want %d", got, id)
+	}
+}
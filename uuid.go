@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// NextUUIDv7 生成一个 id，并编码为 UUID v7（RFC 9562）
+// UUID v7 和雪花算法一样是按时间排序的，结构为：
+//
+//	unix_ts_ms(48)--ver(4)--sequence(12)--var(2)--workerID(62)
+//
+// 为了兼容其它 UUID v7 的实现，时间部分使用 Unix 毫秒时间（即 id 的时间部分加上 epoch），
+// 序列号最多 12 位，超过时返回错误
+func (s *Snowflake) NextUUIDv7() (u [16]byte, err error) {
+	if s.bitLenSequence > 12 {
+		return u, fmt.Errorf("snowflake: %d sequence bits do not fit in uuid v7", s.bitLenSequence)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err = s.nextID(); err != nil {
+		return u, err
+	}
+
+	ms := s.time + s.epoch
+	binary.BigEndian.PutUint64(u[0:8], uint64(ms)<<16|0x7<<12|uint64(s.sequenceField()))
+	binary.BigEndian.PutUint64(u[8:16], 0b10<<62|uint64(s.workerID))
+
+	return u, nil
+}
+
+// ParseUUIDv7 解析 NextUUIDv7 生成的 UUID，返回 Unix 毫秒时间、workerID 和序列号
+func ParseUUIDv7(u [16]byte) (timeMs, workerID, seq int64, err error) {
+	hi := binary.BigEndian.Uint64(u[0:8])
+	lo := binary.BigEndian.Uint64(u[8:16])
+
+	if hi>>12&0xf != 0x7 {
+		return 0, 0, 0, errors.New("snowflake: not a version 7 uuid")
+	}
+	if lo>>62 != 0b10 {
+		return 0, 0, 0, errors.New("snowflake: invalid uuid variant")
+	}
+
+	return int64(hi >> 16), int64(lo & (1<<62 - 1)), int64(hi & 0xfff), nil
+}
+
+// gregorianOffset 1582-10-15 到 1970-01-01 之间的 100 纳秒数
+const gregorianOffset = 0x01B21DD213814000
+
+// NextTimeUUID 生成一个 id，并编码为 UUID v1，兼容 Cassandra 的 TimeUUID
+// UUID v1 的时间为 1582-10-15 以来的 100 纳秒数，这里使用 id 的 Unix 毫秒时间加上序列号（作为毫秒内的 100 纳秒数）换算得到，
+// 因此序列号最多 13 位，同一毫秒内的序列号仍然有序
+// node 部分为 workerID，可以替代 Cassandra 的 now()，并且跨数据中心唯一
+func (s *Snowflake) NextTimeUUID() (u [16]byte, err error) {
+	if s.bitLenSequence > 13 {
+		return u, fmt.Errorf("snowflake: %d sequence bits do not fit in time uuid", s.bitLenSequence)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, err = s.nextID(); err != nil {
+		return u, err
+	}
+
+	ts := uint64(s.time+s.epoch)*10000 + uint64(s.sequenceField()) + gregorianOffset
+
+	// time_low、time_mid、time_hi_and_version
+	binary.BigEndian.PutUint32(u[0:4], uint32(ts))
+	binary.BigEndian.PutUint16(u[4:6], uint16(ts>>32))
+	binary.BigEndian.PutUint16(u[6:8], uint16(ts>>48)&0x0fff|0x1000)
+	// clock_seq 为 0，只设置 variant
+	u[8] = 0x80
+	// node
+	w := uint64(s.workerID)
+	u[10], u[11], u[12], u[13], u[14], u[15] = byte(w>>40), byte(w>>32), byte(w>>24), byte(w>>16), byte(w>>8), byte(w)
+
+	return u, nil
+}
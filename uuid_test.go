@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextUUIDv7(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	before := time.Now().UnixMilli()
+	u, err := s.NextUUIDv7()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ms, workerID, seq, err := ParseUUIDv7(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms < before || ms > time.Now().UnixMilli() {
+		t.Errorf("time %d out of range", ms)
+	}
+	if workerID != s.WorkerID() || seq != s.SequenceID() {
+		t.Errorf("got worker %d seq %d, want %d %d", workerID, seq, s.WorkerID(), s.SequenceID())
+	}
+
+	u[6] = 0x40
+	if _, _, _, err = ParseUUIDv7(u); err == nil {
+		t.Error("expected error for wrong version")
+	}
+}
+
+func TestNextTimeUUID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	u, err := s.NextTimeUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u[6]>>4 != 1 || u[8]>>6 != 0b10 {
+		t.Fatalf("invalid version or variant: %x", u)
+	}
+
+	ts := uint64(u[6]&0x0f)<<56 | uint64(u[7])<<48 | uint64(u[4])<<40 | uint64(u[5])<<32 |
+		uint64(u[0])<<24 | uint64(u[1])<<16 | uint64(u[2])<<8 | uint64(u[3])
+	ms := int64(ts-gregorianOffset) / 10000
+	if now := time.Now().UnixMilli(); ms > now || ms < now-1000 {
+		t.Errorf("time %d not close to %d", ms, now)
+	}
+	if w := int64(u[14])<<8 | int64(u[15]); w != s.WorkerID() {
+		t.Errorf("node = %d, want %d", w, s.WorkerID())
+	}
+}
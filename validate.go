@@ -0,0 +1,213 @@
+package snowflake
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// issue 配置检查发现的问题
+type issue struct {
+	// 描述
+	msg string
+	// 是否是错误，错误会导致 NewSnowflake 失败，否则只是警告
+	fatal bool
+}
+
+// Validate 检查配置，返回所有的警告和错误，错误以 "error: " 开头，警告以 "warning: " 开头
+// NewSnowflake 会在第一个错误时失败，也可以在 main 中单独调用，提前发现配置问题
+// 检查的内容有：
+// 1. 各部分长度之和不为 63
+// 2. workerID 超出 workerID 部分的范围，或者和区域 id、分组标签重叠
+// 3. epoch 在未来
+// 4. 时间部分已经溢出，或者不到一年就会溢出
+// 5. 类型标签、优先级、校验和、序列号偏移量超出范围
+// 6. 设置了非自增，生成的 id 不再递增
+func (s *Snowflake) Validate() []string {
+	var res []string
+
+	for _, i := range s.check() {
+		if i.fatal {
+			res = append(res, "error: "+i.msg)
+		} else {
+			res = append(res, "warning: "+i.msg)
+		}
+	}
+
+	return res
+}
+
+// check 检查配置
+func (s *Snowflake) check() []issue {
+	var res []issue
+
+	add := func(fatal bool, format string, a ...interface{}) {
+		res = append(res, issue{msg: fmt.Sprintf(format, a...), fatal: fatal})
+	}
+
+	if s.bitLenTime <= 0 || s.bitLenWorkerID < 0 || s.bitLenSequence <= 0 {
+		add(true, "bit lengths must be positive, got time=%d worker=%d sequence=%d", s.bitLenTime, s.bitLenWorkerID, s.bitLenSequence)
+	}
+	if sum := s.bitLenTime + s.bitLenWorkerID + s.bitLenSequence; sum != 63 {
+		add(true, "bit lengths sum to %d, want 63", sum)
+	}
+
+	if s.workerID < 0 || s.workerID >= 1<<s.bitLenWorkerID {
+		add(true, "worker id %d out of range [0, %d)", s.workerID, int64(1)<<s.bitLenWorkerID)
+	}
+	if s.regionBits < 0 || s.regionBits > 0 && s.regionBits >= s.bitLenWorkerID {
+		add(true, "region bits %d out of range [0, %d)", s.regionBits, s.bitLenWorkerID)
+	} else if s.region < 0 || s.region >= 1<<s.regionBits {
+		add(true, "region id %d does not fit in %d bits", s.region, s.regionBits)
+	} else if s.regionBits > 0 && s.workerID>>(s.bitLenWorkerID-s.regionBits) != s.region {
+		add(true, "worker id does not fit in %d bits below the region id", s.bitLenWorkerID-s.regionBits)
+	}
+	if s.groupBits < 0 || s.groupBits > 8 || s.groupBits > 0 && s.groupBits >= s.bitLenWorkerID {
+		add(true, "group bits %d out of range [0, %d)", s.groupBits, s.bitLenWorkerID)
+	} else if s.groupBits > 0 && s.regionBits > 0 {
+		add(true, "group bits cannot be combined with region id")
+	} else if s.groupBits > 0 && s.workerID >= 1<<(s.bitLenWorkerID-s.groupBits) {
+		add(true, "worker id %d does not fit in %d bits below the group tag", s.workerID, s.bitLenWorkerID-s.groupBits)
+	}
+
+	now := s.clock()
+	if s.epoch > now {
+		add(true, "epoch %d is in the future", s.epoch)
+	} else if s.bitLenTime > 0 && s.bitLenTime < 63 {
+		// 按毫秒比较，时间部分较长时换算成 time.Duration 会溢出
+		left := int64(1)<<s.bitLenTime - 1 - (now - s.epoch)
+		if left <= 0 {
+			add(true, "time bits have overflowed, epoch %d is too old for %d time bits", s.epoch, s.bitLenTime)
+		} else if left < (365 * 24 * time.Hour).Milliseconds() {
+			add(false, "time bits will overflow in %s", time.Duration(left)*time.Millisecond)
+		}
+	}
+
+	if s.tagBits < 0 || s.tagBits >= s.bitLenSequence {
+		add(true, "type tag bits %d out of range [0, %d)", s.tagBits, s.bitLenSequence)
+	} else if s.tag < 0 || s.tag >= 1<<s.tagBits {
+		add(true, "type tag %d does not fit in %d bits", s.tag, s.tagBits)
+	}
+	if s.priorityBits < 0 || s.tagBits+s.priorityBits >= s.bitLenSequence {
+		add(true, "priority bits %d do not fit in %d sequence bits", s.priorityBits, s.bitLenSequence-s.tagBits)
+	}
+	if s.checksumBits < 0 || s.checksumBits > maxChecksumBits {
+		add(true, "checksum bits %d out of range [0, %d]", s.checksumBits, maxChecksumBits)
+	} else if s.tagBits+s.priorityBits+s.checksumBits >= s.bitLenSequence {
+		add(true, "checksum bits %d do not fit in %d sequence bits", s.checksumBits, s.bitLenSequence-s.tagBits-s.priorityBits)
+	}
+
+	// 长度不合法时上面已经报错，不再计算计数部分的范围
+	if reserved := s.tagBits + s.priorityBits + s.checksumBits; s.tagBits >= 0 && s.priorityBits >= 0 && s.checksumBits >= 0 && reserved < s.bitLenSequence {
+		if s.sequenceOffset < 0 || s.sequenceOffset > s.counterMask() {
+			add(true, "replica sequence offset %d out of range [0, %d]", s.sequenceOffset, s.counterMask())
+		}
+	}
+
+	if s.entityKindBits < 0 || s.entityKindBits > 8 || s.entityIDBits < 0 || s.entityKindBits+s.entityIDBits > s.bitLenWorkerID {
+		add(true, "entity bits kind=%d id=%d do not fit in %d worker bits", s.entityKindBits, s.entityIDBits, s.bitLenWorkerID)
+	}
+
+	if s.nonIncrement {
+		add(false, "nonIncrement is set, ids are not monotonically increasing")
+	}
+
+	return res
+}
+
+// idTolerance ValidateID 允许 id 的时间比当前时间晚多少，用于容忍机器之间的时钟误差
+const idTolerance = time.Second
+
+// ValidateID 校验 id 是否由本实例生成，用于拒绝来自用户输入、webhook 等不可信来源的伪造 id
+// 检查的内容有：
+// 1. id 不能为负数，也不能超出各部分长度之和
+// 2. 时间部分不能为 0，也不能晚于当前时间 1s 以上
+// 3. workerID 部分必须等于本实例的 workerID
+func (s *Snowflake) ValidateID(id int64) error {
+	t, w, seq := s.decompose(id)
+
+	// 设置了倒序时，先还原被取反的时间部分
+	if s.descending && id >= 0 {
+		id = s.compose(1<<s.bitLenTime-1-t, w, seq)
+	}
+	if err := validateID(id, s.Layout(), s.epoch, s.clock(), idTolerance); err != nil {
+		return err
+	}
+
+	if w != s.workerID {
+		return fmt.Errorf("snowflake: id %d has worker id %d, want %d", id, w, s.workerID)
+	}
+
+	return nil
+}
+
+// ValidateIDLoose 和 ValidateID 一样校验 id，但是不检查 workerID，任何 worker 生成的 id 都可以通过
+func ValidateIDLoose(id int64, layout BitLayout, epoch int64, tolerance time.Duration) error {
+	return validateID(id, layout, epoch, time.Now().UnixMilli(), tolerance)
+}
+
+// validateID 校验 id 的范围和时间部分，now 为当前的绝对时间，单位毫秒
+func validateID(id int64, layout BitLayout, epoch, now int64, tolerance time.Duration) error {
+	if id < 0 {
+		return fmt.Errorf("snowflake: id %d is negative", id)
+	}
+	if bits := layout.Time + layout.WorkerID + layout.Sequence; bits < 63 && id >= 1<<bits {
+		return fmt.Errorf("snowflake: id %d overflows %d bits", id, bits)
+	}
+
+	t, _, _ := layout.Decompose(id)
+	if t == 0 {
+		return fmt.Errorf("snowflake: id %d has zero time", id)
+	}
+	if limit := now - epoch + tolerance.Milliseconds(); t > limit {
+		return fmt.Errorf("snowflake: id %d time %d is in the future", id, t+epoch)
+	}
+
+	return nil
+}
+
+// BatchValidate 并行地用 ValidateID 校验一批 id，用于批量导入等场景，errs[i] 不为 nil 表示 ids[i] 校验失败
+// 按 runtime.NumCPU() 个 goroutine 划分，validCount 为通过校验的数量，方便打印汇总日志
+func (s *Snowflake) BatchValidate(ids []int64) (errs []error, validCount int) {
+	errs = make([]error, len(ids))
+	if len(ids) == 0 {
+		return errs, 0
+	}
+
+	workers := runtime.NumCPU()
+	if workers > len(ids) {
+		workers = len(ids)
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		size  = (len(ids) + workers - 1) / workers
+	)
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		wg.Add(1)
+		go func(start, end int) {
+			defer wg.Done()
+
+			valid := 0
+			for i := start; i < end; i++ {
+				if errs[i] = s.ValidateID(ids[i]); errs[i] == nil {
+					valid++
+				}
+			}
+
+			mutex.Lock()
+			validCount += valid
+			mutex.Unlock()
+		}(start, end)
+	}
+	wg.Wait()
+
+	return errs, validCount
+}
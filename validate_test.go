@@ -0,0 +1,108 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+	if res := s.Validate(); len(res) != 0 {
+		t.Errorf("default config: %v", res)
+	}
+
+	s, err = NewSnowflake(WithNonIncrement())
+	if err != nil {
+		panic(err)
+	}
+	if res := s.Validate(); len(res) != 1 || !strings.HasPrefix(res[0], "warning: ") {
+		t.Errorf("nonIncrement: %v", res)
+	}
+
+	bad := [][]Option{
+		{WithLen(41, 12, 12)},
+		{WithWorkID(func() (int64, error) { return 1 << 12, nil })},
+		{WithEpoch(time.Now().Add(time.Hour).UnixMilli())},
+		{WithLen(20, 33, 10)},
+	}
+	for i, opts := range bad {
+		if _, err = NewSnowflake(opts...); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	for _, opts := range [][]Option{nil, {WithDescendingSequence()}} {
+		s, err := NewSnowflake(append(opts, WithWorkID(func() (int64, error) { return 5, nil }))...)
+		if err != nil {
+			panic(err)
+		}
+
+		if err = s.ValidateID(next(t, s)); err != nil {
+			t.Errorf("valid id: %v", err)
+		}
+	}
+
+	s, _ := NewSnowflake(WithWorkID(func() (int64, error) { return 5, nil }))
+	now := time.Now().UnixMilli() - s.epoch
+	future := now + time.Hour.Milliseconds()
+
+	for _, id := range []int64{
+		-1,
+		s.compose(0, 5, 1),
+		s.compose(future, 5, 1),
+		s.compose(now, 6, 1),
+	} {
+		if err := s.ValidateID(id); err == nil {
+			t.Errorf("id %d: expected error", id)
+		}
+	}
+
+	other := s.compose(now, 6, 1)
+	if err := ValidateIDLoose(other, s.Layout(), s.epoch, time.Second); err != nil {
+		t.Errorf("loose validation: %v", err)
+	}
+	if err := ValidateIDLoose(s.compose(future, 6, 1), s.Layout(), s.epoch, time.Second); err == nil {
+		t.Error("expected error for future id")
+	}
+
+	small := BitLayout{Time: 39, WorkerID: 8, Sequence: 8}
+	if err := ValidateIDLoose(1<<55, small, 0, time.Second); err == nil {
+		t.Error("expected error for overflowing id")
+	}
+}
+
+func TestBatchValidate(t *testing.T) {
+	s, err := NewSnowflake(WithWorkID(func() (int64, error) { return 5, nil }))
+	if err != nil {
+		panic(err)
+	}
+
+	ids := make([]int64, 1000)
+	for i := range ids {
+		ids[i] = next(t, s)
+	}
+	bad := []int{0, 17, 999}
+	for _, i := range bad {
+		ids[i] = -ids[i]
+	}
+
+	errs, valid := s.BatchValidate(ids)
+	if valid != len(ids)-len(bad) {
+		t.Errorf("valid = %d, want %d", valid, len(ids)-len(bad))
+	}
+	for _, i := range bad {
+		if errs[i] == nil {
+			t.Errorf("id %d: expected error", i)
+		}
+	}
+
+	if errs, valid = s.BatchValidate(nil); len(errs) != 0 || valid != 0 {
+		t.Errorf("empty batch: %d errors, %d valid", len(errs), valid)
+	}
+}
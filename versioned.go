@@ -0,0 +1,31 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// NextVersionedID 生成一个事件溯源中 (聚合 id, 版本号) 对应的事件 id
+// 时间部分为当前时间，用于全局排序；workerID 部分为 entityID，同一个聚合的事件局部相邻；序列号部分为 version
+// entityID 超出 workerID 部分的范围，或者 version 超出序列号部分的范围时返回错误
+// 注意不会推进序列号，同一毫秒内相同的 (entityID, version) 会得到相同的 id，可以用于去重
+func (s *Snowflake) NextVersionedID(entityID int64, version int) (int64, error) {
+	if entityID < 0 || entityID >= 1<<s.bitLenWorkerID {
+		return 0, fmt.Errorf("snowflake: entity id %d out of range [0, %d)", entityID, int64(1)<<s.bitLenWorkerID)
+	}
+	if version < 0 || int64(version) > s.sequenceMask {
+		return 0, fmt.Errorf("snowflake: version %d out of range [0, %d]", version, s.sequenceMask)
+	}
+
+	t, err := s.timeField(time.UnixMilli(s.clock()))
+	if err != nil {
+		return 0, err
+	}
+
+	return s.compose(t, entityID, int64(version)), nil
+}
+
+// ParseVersionedID 解析 NextVersionedID 生成的 id，timeMs 为距离 epoch 的毫秒数
+func ParseVersionedID(id int64, layout BitLayout) (timeMs, entityID, version int64) {
+	return layout.Decompose(id)
+}
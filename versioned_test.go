@@ -0,0 +1,34 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextVersionedID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	before := time.Now().UnixMilli() - s.epoch
+	id, err := s.NextVersionedID(42, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	timeMs, entityID, version := ParseVersionedID(id, s.Layout())
+	if entityID != 42 || version != 7 {
+		t.Errorf("got (%d, %d), want (42, 7)", entityID, version)
+	}
+	if timeMs < before || timeMs > time.Now().UnixMilli()-s.epoch {
+		t.Errorf("time %d out of range", timeMs)
+	}
+
+	if _, err = s.NextVersionedID(1<<s.bitLenWorkerID, 0); err == nil {
+		t.Error("expected error for entity id out of range")
+	}
+	if _, err = s.NextVersionedID(0, int(s.sequenceMask)+1); err == nil {
+		t.Error("expected error for version out of range")
+	}
+}
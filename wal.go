@@ -0,0 +1,21 @@
+package snowflake
+
+// NextWALSequenceNumber 生成预写日志的 LSN，同时返回上一条日志的 LSN
+// 两者在同一次加锁中得到，调用方把 lsn 和 prevLsn 都写入日志头，就能形成一条反向的链表
+// 第一条日志的 prevLsn 为 0
+func (s *Snowflake) NextWALSequenceNumber() (lsn, prevLsn int64, err error) {
+	return s.nextIDWithPrev()
+}
+
+// nextIDWithPrev 生成一个 id，同时返回上一次生成的 id
+func (s *Snowflake) nextIDWithPrev() (id, prev int64, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	prev = s.lastID
+	if id, err = s.nextID(); err != nil {
+		return 0, 0, err
+	}
+
+	return id, prev, nil
+}
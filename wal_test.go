@@ -0,0 +1,33 @@
+package snowflake
+
+import "testing"
+
+func TestNextWALSequenceNumber(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	lsn, prev, err := s.NextWALSequenceNumber()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prev != 0 {
+		t.Errorf("first prevLsn = %d, want 0", prev)
+	}
+
+	for i := 0; i < 10; i++ {
+		next, prev, err := s.NextWALSequenceNumber()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if prev != lsn || next <= lsn {
+			t.Fatalf("got (%d, %d) after %d", next, prev, lsn)
+		}
+		lsn = next
+	}
+
+	if s.LastID() != lsn {
+		t.Errorf("LastID = %d, want %d", s.LastID(), lsn)
+	}
+}
@@ -0,0 +1,23 @@
+package snowflake
+
+import "fmt"
+
+// Warmup 生成并丢弃 n 个 id，推进序列号，让应用真正生成的 id 不从序列号 0 开始
+// 启动时大量 id 集中在序列号 0 附近，可能在某些 B 树索引中形成热点，预热可以分散最初的写入
+// 注意这只是一个很小的优化，只对启动时就有百万级写入的系统有意义，进入下一毫秒后序列号仍然会从头开始
+func (s *Snowflake) Warmup(n int) error {
+	if n < 0 {
+		return fmt.Errorf("snowflake: invalid count %d", n)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i := 0; i < n; i++ {
+		if _, err := s.nextID(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	now := time.Now().UnixMilli()
+	s, err := NewSnowflake(WithClock(func() int64 { return now }))
+	if err != nil {
+		panic(err)
+	}
+
+	if err = s.Warmup(100); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, seq := s.decompose(next(t, s)); seq != 100 {
+		t.Errorf("sequence = %d, want 100", seq)
+	}
+
+	if err = s.Warmup(-1); err == nil {
+		t.Error("expected error for negative count")
+	}
+}
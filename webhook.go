@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+)
+
+// webhookPrefixLen 参与计算的 payload 前缀长度
+const webhookPrefixLen = 4
+
+// NextWebhookID 生成一个 webhook 投递的幂等 key，编码为 16 进制
+// 序列号的低 bitLenSequence/2 位会异或上 fnv32(eventType + ":" + payload 的前 4 个字节)，
+// 相同的事件类型和 payload 前缀在序列号部分的贡献相同，不同毫秒的时间部分仍然不同
+// 注意序列号部分被改写，同一毫秒内可能和其它 id 重复，不要和普通 id 混用
+func (s *Snowflake) NextWebhookID(eventType string, payload []byte) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	id, err := s.nextID()
+	if err != nil {
+		return "", err
+	}
+
+	if len(payload) > webhookPrefixLen {
+		payload = payload[:webhookPrefixLen]
+	}
+	h := fnv.New32()
+	_, _ = h.Write([]byte(eventType + ":" + string(payload)))
+
+	t, w, seq := s.decompose(id)
+	seq ^= int64(h.Sum32()) & (1<<(s.bitLenSequence/2) - 1)
+
+	return strconv.FormatInt(s.compose(t, w, seq), 16), nil
+}
+
+// ParseWebhookID 解析 NextWebhookID 生成的 key
+func ParseWebhookID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("snowflake: invalid webhook id %q: %w", s, err)
+	}
+	return id, nil
+}
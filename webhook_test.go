@@ -0,0 +1,35 @@
+package snowflake
+
+import "testing"
+
+func TestNextWebhookID(t *testing.T) {
+	s, err := NewSnowflake()
+	if err != nil {
+		panic(err)
+	}
+
+	key, err := s.NextWebhookID("order.created", []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := ParseWebhookID(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gt, gw, gs := s.decompose(id)
+	lt, lw, ls := s.decompose(s.LastID())
+	if gt != lt || gw != lw {
+		t.Errorf("time and worker should be unchanged")
+	}
+
+	low := int64(1<<(s.bitLenSequence/2) - 1)
+	if gs&^low != ls&^low {
+		t.Errorf("high sequence bits should be unchanged")
+	}
+
+	if _, err = ParseWebhookID("xyz"); err == nil {
+		t.Error("expected error")
+	}
+}